@@ -0,0 +1,75 @@
+package workgroup
+
+import (
+	"slices"
+	"sync"
+)
+
+// GoActor launches an actor within the workgroup, following the model of
+// github.com/oklog/run: execute runs the actor and interrupt must make
+// execute return. When the first actor of the workgroup returns, the
+// interrupt functions of all actors are called with its error, nil
+// included, so that all of them stop. An actor added afterwards is
+// interrupted as soon as it is launched.
+//
+// It makes the workgroup usable as the lifecycle manager of a service,
+// e.g. with an HTTP server and a signal handler as actors, while the
+// errors of the actors are collected according to the failure mode, and
+// actors are subject to the concurrency limits. Actors are not retried,
+// whatever the TaskRetry option among opts.
+func (g *Group) GoActor(execute func() error, interrupt func(error), opts ...TaskOption) {
+	g.errLock.Lock()
+	if g.actors == nil {
+		g.actors = &actors{}
+	}
+	a := g.actors
+	g.errLock.Unlock()
+
+	a.add(interrupt)
+	g.Go(g.context(), func() error {
+		err := execute()
+		a.interrupt(err)
+		return err
+	}, append(slices.Clip(opts), TaskRetry(Attempts(1)))...)
+}
+
+// actors are the actors of a workgroup.
+type actors struct {
+	mu          sync.Mutex
+	interrupts  []func(error)
+	interrupted bool
+	cause       error
+}
+
+// add registers the interrupt function of an actor, calling it
+// immediately if the actors have already been interrupted.
+func (a *actors) add(interrupt func(error)) {
+	a.mu.Lock()
+	if !a.interrupted {
+		a.interrupts = append(a.interrupts, interrupt)
+		a.mu.Unlock()
+		return
+	}
+	cause := a.cause
+	a.mu.Unlock()
+	interrupt(cause)
+}
+
+// interrupt interrupts all actors with err, unless they have already
+// been interrupted.
+func (a *actors) interrupt(err error) {
+	a.mu.Lock()
+	if a.interrupted {
+		a.mu.Unlock()
+		return
+	}
+	a.interrupted = true
+	a.cause = err
+	interrupts := a.interrupts
+	a.interrupts = nil
+	a.mu.Unlock()
+
+	for _, interrupt := range interrupts {
+		interrupt(err)
+	}
+}
@@ -0,0 +1,57 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestGroup_GoActor(t *testing.T) {
+	_, g := New(context.Background(), Collect)
+
+	var (
+		mu     sync.Mutex
+		causes []error
+	)
+	for i := 0; i < 3; i++ {
+		stop := make(chan struct{})
+		g.GoActor(func() error {
+			<-stop
+			return nil
+		}, func(err error) {
+			mu.Lock()
+			causes = append(causes, err)
+			mu.Unlock()
+			close(stop)
+		})
+	}
+	g.GoActor(func() error {
+		return errInternal
+	}, func(err error) {
+		mu.Lock()
+		causes = append(causes, err)
+		mu.Unlock()
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if len(causes) != 4 {
+		t.Fatalf("expected all 4 actors to be interrupted, but got %d", len(causes))
+	}
+	for _, cause := range causes {
+		if !errors.Is(cause, errInternal) {
+			t.Errorf("expected the actors to be interrupted with %v, but got %v", errInternal, cause)
+		}
+	}
+
+	// An actor added after the interruption is interrupted right away.
+	var late error
+	g.GoActor(func() error { return nil }, func(err error) { late = err })
+	if !errors.Is(late, errInternal) {
+		t.Errorf("expected the late actor to be interrupted with %v, but got %v", errInternal, late)
+	}
+	g.Wait()
+}
@@ -0,0 +1,85 @@
+package workgroup
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithAuditLog writes one line of JSON per lifecycle event of the
+// goroutines of the workgroup to w, as notified to a Listener, e.g.
+//
+//	{"time":"2024-01-01T00:00:00Z","event":"retry","id":"3f2a...","index":3,"name":"sync-users","attempts":1,"delay":"1s","error":"timeout"}
+//
+// The events are "submit", "start", "retry", "panic" and "finish". The
+// events of a goroutine share its "id", while its "index" is only
+// assigned once it is launched, so it is -1 in the "submit" event. It is
+// simpler than metrics or tracing for the post-run analysis of one-shot
+// batch jobs. Errors writing to w are ignored.
+func WithAuditLog(w io.Writer) Option {
+	return func(g *Group) {
+		g.listeners = append(g.listeners, &auditLog{g: g, w: w})
+	}
+}
+
+// auditLog is a Listener writing the events to a writer.
+type auditLog struct {
+	g  *Group
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// auditEvent is the JSON representation of an event.
+type auditEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	ID       string    `json:"id"`
+	Index    int       `json:"index"`
+	Name     string    `json:"name,omitempty"`
+	Attempts int       `json:"attempts"`
+	Delay    string    `json:"delay,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (a *auditLog) OnSubmit(info TaskInfo) {
+	a.write("submit", info, nil, "")
+}
+
+func (a *auditLog) OnStart(info TaskInfo) {
+	a.write("start", info, nil, "")
+}
+
+func (a *auditLog) OnRetry(info TaskInfo, err error, delay time.Duration) {
+	a.write("retry", info, err, delay.String())
+}
+
+func (a *auditLog) OnPanic(info TaskInfo, err *PanicError) {
+	a.write("panic", info, err, "")
+}
+
+func (a *auditLog) OnFinish(info TaskInfo, err error) {
+	a.write("finish", info, err, "")
+}
+
+func (a *auditLog) write(event string, info TaskInfo, err error, delay string) {
+	e := auditEvent{
+		Time:     a.g.clock().Now(),
+		Event:    event,
+		ID:       info.ID,
+		Index:    info.Index,
+		Name:     info.Name,
+		Attempts: info.Attempts,
+		Delay:    delay,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	b, jsonErr := json.Marshal(e)
+	if jsonErr != nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.w.Write(append(b, '\n'))
+}
@@ -0,0 +1,40 @@
+package workgroup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, g := New(context.Background(), Collect,
+		WithClock(&fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}),
+		WithAuditLog(&buf),
+		WithRetry(Attempts(2), Delay(time.Second), MaxJitter(0)),
+	)
+
+	g.GoNamed(ctx, "sync-users", func() error {
+		return errInternal
+	})
+	g.Wait()
+
+	var events []string
+	var last auditEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		if err := dec.Decode(&last); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, last.Event)
+	}
+	if want := []string{"submit", "start", "retry", "finish"}; !slices.Equal(events, want) {
+		t.Errorf("expected the events %q, but got %q", want, events)
+	}
+	if last.Name != "sync-users" || last.Attempts != 2 || last.ID == "" || last.Error != `task "sync-users": internal` {
+		t.Errorf("unexpected finish event: %+v", last)
+	}
+}
@@ -0,0 +1,58 @@
+package workgroup
+
+import "sync"
+
+// Barrier blocks until all goroutines submitted to the workgroup so far,
+// including queued ones, have completed, and returns the current error of
+// the workgroup, see Err. Unlike Wait, it does not cancel the context of
+// the workgroup nor finish it, so the goroutines submitted afterwards form
+// the next phase of a multi-phase job, e.g. extract, transform and load,
+// within a single workgroup:
+//
+//	for _, src := range sources {
+//		g.Go(ctx, extract(src))
+//	}
+//	if err := g.Barrier(); err != nil {
+//		return err
+//	}
+//	for _, rec := range records {
+//		g.Go(ctx, transform(rec))
+//	}
+//
+// Goroutines submitted concurrently with Barrier, e.g. by other
+// producers, are waited for as well.
+func (g *Group) Barrier() error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	for g.inflight > 0 {
+		if g.idle == nil {
+			g.idle = sync.NewCond(&g.errLock)
+		}
+		g.idle.Wait()
+	}
+	return g.result()
+}
+
+// enter adds a goroutine that Wait and Barrier wait for.
+func (g *Group) enter() {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	g.enterLocked()
+}
+
+// enterLocked is like enter. g.errLock must be held.
+func (g *Group) enterLocked() {
+	g.inflight++
+	g.wg.Add(1)
+}
+
+// leave marks a goroutine added with enter as completed.
+func (g *Group) leave() {
+	g.errLock.Lock()
+	g.inflight--
+	if g.inflight == 0 && g.idle != nil {
+		g.idle.Broadcast()
+	}
+	g.errLock.Unlock()
+	g.wg.Done()
+}
@@ -0,0 +1,46 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroup_Barrier(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2), WithQueue(10, QueueBlock))
+
+	var extracted atomic.Int32
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			extracted.Add(1)
+			return nil
+		})
+		g.Submit(ctx, func() error {
+			extracted.Add(1)
+			return nil
+		})
+	}
+	if err := g.Barrier(); err != nil {
+		t.Fatalf("group.Barrier() = %v, want nil", err)
+	}
+	if n := extracted.Load(); n != 10 {
+		t.Fatalf("expected all 10 goroutines of the first phase to complete, but got %d", n)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected the barrier not to cancel the workgroup")
+	}
+
+	g.Go(ctx, func() error {
+		if n := extracted.Load(); n != 10 {
+			t.Errorf("expected the second phase to start after the first, but got %d", n)
+		}
+		return errInternal
+	})
+	if err := g.Barrier(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Barrier() = %v, want %v", err, errInternal)
+	}
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+}
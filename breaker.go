@@ -0,0 +1,68 @@
+package workgroup
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is the error of the goroutines short-circuited by the
+// circuit breaker of a workgroup, see WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("workgroup: circuit breaker is open")
+
+// WithCircuitBreaker adds a circuit breaker to the workgroup. After
+// threshold consecutive failed attempts, the circuit opens and every
+// attempt fails immediately with ErrCircuitOpen, without being executed
+// or retried, until cooldown has elapsed. The next attempt is then
+// executed: a success closes the circuit again, while a failure reopens
+// it for another cooldown.
+//
+// It avoids hammering a single flaky dependency with a large number of
+// goroutines that are bound to fail.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(g *Group) {
+		g.breaker = &circuitBreaker{
+			threshold: max(threshold, 1),
+			cooldown:  cooldown,
+		}
+	}
+}
+
+// circuitBreaker counts the consecutive failed attempts of the tasks of a
+// workgroup.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// wrap returns a function that executes fn unless the circuit is open.
+func (b *circuitBreaker) wrap(clock Clock, fn func() error) func() error {
+	return func() error {
+		b.mu.Lock()
+		open := clock.Now().Before(b.openUntil)
+		b.mu.Unlock()
+		if open {
+			return Permanent(ErrCircuitOpen)
+		}
+
+		err := fn()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if err == nil {
+			b.failures = 0
+			return nil
+		}
+		// The count is only reset by a success, so that a failure right
+		// after the cooldown reopens the circuit.
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = clock.Now().Add(b.cooldown)
+		}
+		return err
+	}
+}
@@ -0,0 +1,67 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithCircuitBreaker(t *testing.T) {
+	ctx, g := New(context.Background(), Collect,
+		WithLimit(1),
+		WithCircuitBreaker(3, time.Hour),
+	)
+
+	// The limit of 1 runs the goroutines one after the other.
+	var count int
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			count++
+			return errInternal
+		})
+	}
+	g.Wait()
+
+	if count != 3 {
+		t.Errorf("expected 3 executed goroutines, but got %d", count)
+	}
+	var open int
+	for _, err := range g.Errors() {
+		if errors.Is(err, ErrCircuitOpen) {
+			open++
+		}
+	}
+	if open != 2 {
+		t.Errorf("expected 2 short-circuited goroutines, but got %d", open)
+	}
+}
+
+func TestCircuitBreaker_Cooldown(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := &circuitBreaker{threshold: 2, cooldown: time.Minute}
+
+	steps := []struct {
+		advance time.Duration
+		err     error
+		want    error
+	}{
+		{err: errInternal, want: errInternal},
+		{err: nil, want: nil}, // Resets the count.
+		{err: errInternal, want: errInternal},
+		{err: errInternal, want: errInternal}, // Opens the circuit.
+		{err: nil, want: ErrCircuitOpen},
+		{advance: time.Minute, err: errInternal, want: errInternal}, // Reopens.
+		{err: nil, want: ErrCircuitOpen},
+		{advance: time.Minute, err: nil, want: nil}, // Closes.
+		{err: errInternal, want: errInternal},
+		{err: nil, want: nil},
+	}
+	for i, s := range steps {
+		clock.advance(s.advance)
+		err := b.wrap(clock, func() error { return s.err })()
+		if !errors.Is(err, s.want) || (s.want == nil && err != nil) {
+			t.Errorf("step %d: got %v, want %v", i, err, s.want)
+		}
+	}
+}
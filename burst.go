@@ -0,0 +1,76 @@
+package workgroup
+
+import "time"
+
+// WithStartLimit caps the number of goroutines that may start within any
+// window of the given duration to n, e.g. at most 20 starts per 100ms,
+// independently of the concurrency limit. It smooths out spiky admission
+// when many slots free up at once.
+func WithStartLimit(n int, window time.Duration) Option {
+	return func(g *Group) {
+		g.startLimit = &startLimit{
+			starts: make([]time.Time, max(n, 1)),
+			window: window,
+		}
+	}
+}
+
+// startLimit tracks the start times of the most recently admitted
+// goroutines in a ring buffer.
+type startLimit struct {
+	window time.Duration
+	clock  Clock
+
+	starts []time.Time
+	next   int
+	waking bool
+}
+
+// wait returns how long to wait until another goroutine may start.
+func (s *startLimit) wait() time.Duration {
+	// The oldest start of the ring must be out of the window.
+	oldest := s.starts[s.next]
+	if oldest.IsZero() {
+		return 0
+	}
+	return oldest.Add(s.window).Sub(s.clock.Now())
+}
+
+// record records the start of a goroutine.
+func (s *startLimit) record() {
+	s.starts[s.next] = s.clock.Now()
+	s.next = (s.next + 1) % len(s.starts)
+}
+
+// startAllowed reports whether the start limit of l, if any, allows a
+// goroutine to start. Otherwise, it admits the waiters once it does. l.mu
+// must be held.
+func (l *limiter) startAllowed() bool {
+	s := l.startLimit
+	if s == nil {
+		return true
+	}
+	d := s.wait()
+	if d <= 0 {
+		return true
+	}
+	if !s.waking {
+		s.waking = true
+		go func() {
+			<-s.clock.After(d)
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			s.waking = false
+			l.admit()
+		}()
+	}
+	return false
+}
+
+// take takes n for a goroutine starting. l.mu must be held.
+func (l *limiter) take(n int) {
+	l.used += n
+	if l.startLimit != nil {
+		l.startLimit.record()
+	}
+}
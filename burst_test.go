@@ -0,0 +1,24 @@
+package workgroup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithStartLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithStartLimit(5, 50*time.Millisecond))
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		g.Submit(ctx, func() error { return nil })
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	// 4 bursts of 5 starts, 50ms apart.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected the starts to be spread over 150ms, but took %v", elapsed)
+	}
+}
@@ -0,0 +1,42 @@
+package workgroup
+
+import "context"
+
+// GoFromChannel launches a goroutine within the workgroup for every
+// function received from ch, until ch is closed, and blocks meanwhile.
+// Like GoErr, it honors the concurrency limit, which applies
+// backpressure on the producers of ch, and returns the cause of the
+// cancellation once ctx or the context of the workgroup is done. It
+// returns nil once ch is closed.
+//
+// Task options apply to all launched goroutines. GoFromChannel must be
+// called before Wait, e.g. by the goroutine calling Wait.
+func (g *Group) GoFromChannel(ctx context.Context, ch <-chan func() error, opts ...TaskOption) error {
+	return GoChannel(ctx, g, ch, func(_ context.Context, fn func() error) error {
+		return fn()
+	}, opts...)
+}
+
+// GoChannel is like GoFromChannel, but launches fn for every value
+// received from ch. fn receives the context of its task, see GoCtx.
+func GoChannel[T any](ctx context.Context, g *Group, ch <-chan T, fn func(ctx context.Context, v T) error, opts ...TaskOption) error {
+	gctx := g.context()
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			t := g.newTaskContext(func(ctx context.Context) error {
+				return fn(ctx, v)
+			}, opts)
+			if err := g.launchErr(ctx, t); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-gctx.Done():
+			return context.Cause(gctx)
+		}
+	}
+}
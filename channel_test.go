@@ -0,0 +1,70 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroup_GoFromChannel(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	ch := make(chan func() error)
+	var ran atomic.Int32
+	go func() {
+		defer close(ch)
+		for i := range 10 {
+			ch <- func() error {
+				ran.Add(1)
+				if i == 3 {
+					return errInternal
+				}
+				return nil
+			}
+		}
+	}()
+
+	if err := g.GoFromChannel(ctx, ch); err != nil {
+		t.Errorf("group.GoFromChannel() = %v, want nil", err)
+	}
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if n := ran.Load(); n != 10 {
+		t.Errorf("expected 10 functions to run, but got %d", n)
+	}
+}
+
+func TestGoChannel(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	ch := make(chan int)
+	processed := make(chan struct{}, 3)
+	var sum atomic.Int64
+	go func() {
+		for i := 1; i <= 3; i++ {
+			ch <- i
+		}
+		for range 3 {
+			<-processed
+		}
+		g.CancelCause(errInvalid)
+	}()
+
+	err := GoChannel(ctx, g, ch, func(ctx context.Context, v int) error {
+		if _, ok := TaskID(ctx); !ok {
+			t.Error("expected the context of the task")
+		}
+		sum.Add(int64(v))
+		processed <- struct{}{}
+		return nil
+	})
+	if err != errInvalid {
+		t.Errorf("GoChannel() = %v, want %v", err, errInvalid)
+	}
+	g.Wait()
+	if s := sum.Load(); s != 6 {
+		t.Errorf("expected the values to sum up to 6, but got %d", s)
+	}
+}
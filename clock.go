@@ -0,0 +1,123 @@
+package workgroup
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time of a workgroup. It is used to measure the
+// duration of tasks and to wait for delays, such as the backoff between
+// retries. The default clock is the system clock; tests can replace it
+// with WithClock to control time without real sleeps.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer creates a Timer that sends the current time on its channel
+	// after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. It returns false if the timer
+	// has already expired or been stopped.
+	Stop() bool
+}
+
+// WithClock sets the clock used by the workgroup for retry delays, task
+// durations and any other timing. It defaults to the system clock.
+func WithClock(c Clock) Option {
+	return func(g *Group) {
+		g.clk = c
+	}
+}
+
+// systemClock is the Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// withTimeout is like context.WithTimeout, but measures the timeout with
+// clock, so that a fake clock can drive it.
+func withTimeout(parent context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(systemClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+	deadline := clock.Now().Add(d)
+	if pd, ok := parent.Deadline(); ok && pd.Before(deadline) {
+		deadline = pd
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	timer := clock.NewTimer(d)
+	go func() {
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return &timeoutContext{Context: ctx, deadline: deadline}, func() {
+		cancel(context.Canceled)
+	}
+}
+
+// withDeadline is like context.WithDeadline, but measures the time until
+// deadline with clock, see withTimeout.
+func withDeadline(parent context.Context, clock Clock, deadline time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(systemClock); ok {
+		return context.WithDeadline(parent, deadline)
+	}
+	return withTimeout(parent, clock, deadline.Sub(clock.Now()))
+}
+
+// timeoutContext is the context returned by withTimeout, reporting its
+// deadline and context.DeadlineExceeded once it has expired.
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// clock returns the clock of the workgroup, or the system clock if none
+// is set.
+func (g *Group) clock() Clock {
+	if g.clk == nil {
+		return systemClock{}
+	}
+	return g.clk
+}
@@ -0,0 +1,77 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose timers fire immediately, advancing the
+// current time by their duration.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return fakeTimer(ch)
+}
+
+type fakeTimer chan time.Time
+
+func (t fakeTimer) C() <-chan time.Time {
+	return t
+}
+
+func (t fakeTimer) Stop() bool {
+	return false
+}
+
+func TestGroup_WithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx, g := New(context.Background(), Collect,
+		WithClock(clock),
+		WithRetry(Attempts(5), Delay(time.Hour), MaxJitter(0)),
+	)
+
+	start := time.Now()
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+	err := g.Wait()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected retries to use the fake clock, but took %v", elapsed)
+	}
+	// 1h + 2h + 4h + 8h of backoff between the 5 attempts.
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("expected a *TaskError, but got %v", err)
+	}
+	if taskErr.Duration != 15*time.Hour {
+		t.Errorf("expected the task to take 15h, but got %v", taskErr.Duration)
+	}
+}
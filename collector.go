@@ -0,0 +1,44 @@
+package workgroup
+
+import "context"
+
+// Collector is a workgroup in the Collect mode for simple parallel loops,
+// which never cancels its goroutines. It has no context, so that its Go
+// method only takes the function to execute:
+//
+//	c := workgroup.NewCollector(workgroup.WithLimit(8))
+//	for _, file := range files {
+//		c.Go(func() error {
+//			return compress(file)
+//		})
+//	}
+//	err := c.Wait()
+type Collector struct {
+	g *Group
+}
+
+// NewCollector creates a new Collector with the specified options. Unlike
+// New, it does not derive a context, so that options canceling the
+// context of the workgroup, e.g. WithTimeout or WithShutdownSignals, have
+// no effect: in particular, the signals are not handled.
+func NewCollector(opts ...Option) *Collector {
+	g := &Group{failureMode: Collect}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.setup()
+	return &Collector{g: g}
+}
+
+// Go launches a new goroutine within the collector to execute fn, like
+// Group.Go.
+func (c *Collector) Go(fn func() error, opts ...TaskOption) {
+	c.g.Go(context.Background(), fn, opts...)
+}
+
+// Wait blocks until all goroutines launched by the collector have
+// completed, and returns an *AggregateError holding their errors, if
+// any, like Group.Wait.
+func (c *Collector) Wait() error {
+	return c.g.Wait()
+}
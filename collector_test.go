@@ -0,0 +1,38 @@
+package workgroup
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(WithLimit(2))
+
+	var running, peak atomic.Int32
+	for i := range 10 {
+		c.Go(func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			if i%5 == 0 {
+				return errInternal
+			}
+			return nil
+		})
+	}
+
+	err := c.Wait()
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) || len(aggErr.Errors) != 2 || !errors.Is(err, errInternal) {
+		t.Errorf("collector.Wait() = %v, want an *AggregateError of 2 errors", err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 goroutines to run concurrently, but got %d", p)
+	}
+}
@@ -0,0 +1,77 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithTimeout(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithTimeout(20*time.Millisecond))
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("expected the context of the workgroup to have a deadline")
+	}
+	g.Go(ctx, func() error {
+		return nil
+	})
+	g.GoNamed(ctx, "slow", func() error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := g.Wait()
+	var deadlineErr *DeadlineError
+	if !errors.As(err, &deadlineErr) {
+		t.Fatalf("group.Wait() = %v, want a *DeadlineError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to match %v", context.DeadlineExceeded)
+	}
+	if len(deadlineErr.Running) != 1 || deadlineErr.Running[0].Name != "slow" {
+		t.Errorf("expected the slow goroutine to be reported as running, but got %+v", deadlineErr.Running)
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestGroup_WithDeadline_InTime(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithDeadline(time.Now().Add(time.Hour)))
+
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
+
+func TestGroup_WithTimeout_Clock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx, g := New(context.Background(), Collect, WithClock(clock), WithTimeout(time.Hour))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the timeout to be measured with the clock of the workgroup")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("ctx.Err() = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+
+	// Started after the deadline, so it was not running at the deadline.
+	g.Go(ctx, func() error {
+		return nil
+	})
+	var deadlineErr *DeadlineError
+	if err := g.Wait(); errors.As(err, &deadlineErr) {
+		t.Errorf("expected no goroutine to be reported as running at the deadline, but got %+v", deadlineErr.Running)
+	}
+}
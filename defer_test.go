@@ -0,0 +1,80 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGroup_Defer(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast)
+
+	var calls []int
+	for i := 0; i < 3; i++ {
+		g.Defer(func() {
+			calls = append(calls, i)
+		})
+	}
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if want := []int{2, 1, 0}; !slices.Equal(calls, want) {
+		t.Errorf("expected the cleanup functions to be called in order %v, but got %v", want, calls)
+	}
+
+	g.Wait()
+	if len(calls) != 3 {
+		t.Errorf("expected the cleanup functions to be called once, but got %d calls", len(calls))
+	}
+
+	// Functions registered after Wait are called immediately.
+	g.Defer(func() {
+		calls = append(calls, 3)
+	})
+	if len(calls) != 4 {
+		t.Errorf("expected a late cleanup function to be called immediately")
+	}
+}
+
+func TestGroup_WithOnFinish(t *testing.T) {
+	var (
+		calls    int
+		finalErr error
+		stats    Stats
+	)
+	ctx, g := New(context.Background(), Collect, WithOnFinish(func(err error, s Stats) {
+		calls++
+		finalErr, stats = err, s
+	}))
+
+	var cleaned bool
+	g.Defer(func() {
+		cleaned = true
+	})
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+	g.Go(ctx, func() error {
+		return nil
+	})
+
+	err := g.Wait()
+	g.Wait()
+	if calls != 1 {
+		t.Fatalf("expected the callback to be called once, but got %d calls", calls)
+	}
+	if !cleaned {
+		t.Errorf("expected the callback to be called after the cleanup functions")
+	}
+	if !errors.Is(finalErr, errInternal) || finalErr.Error() != err.Error() {
+		t.Errorf("expected the callback to receive %v, but got %v", err, finalErr)
+	}
+	if stats.Succeeded != 1 || stats.Failed != 1 {
+		t.Errorf("expected 1 succeeded and 1 failed goroutine, but got %+v", stats)
+	}
+}
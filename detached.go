@@ -0,0 +1,38 @@
+package workgroup
+
+import "context"
+
+// GoDetached launches fn in a new goroutine for best-effort background
+// work tied to the workgroup, e.g. refreshing a cache or flushing
+// metrics. Unlike Go, Wait does not wait for detached goroutines, which
+// are not subject to the concurrency limits and are not retried; they
+// must stop once the context of the workgroup is canceled, which Wait
+// does when it returns.
+//
+// Detached goroutines are still tracked by the workgroup: a panic in fn
+// is recovered as a *PanicError, failures are reported to the WithOnError
+// callback with an Index of -1, after WithIgnoreErrors and
+// WithErrorMapper have been applied, and Stats counts the running ones. Their
+// errors are not collected, as they may fail after Wait has returned.
+func (g *Group) GoDetached(ctx context.Context, fn func() error) {
+	if g.accepting() != nil {
+		return
+	}
+	t := g.newTask(fn, nil)
+	t.index = -1
+
+	g.detached.Add(1)
+	go func() {
+		defer g.detached.Add(-1)
+		// Reported as is if fn calls runtime.Goexit.
+		err := ErrGoexit
+		defer func() {
+			if err = g.filter(t, err); err != nil && g.onError != nil {
+				g.onError(t.info(), err)
+			}
+		}()
+		ctx, cancel := t.context(g.context(), g.clock())
+		defer cancel()
+		err = g.retryable(ctx, t)()
+	}()
+}
@@ -0,0 +1,76 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGroup_GoDetached(t *testing.T) {
+	reported := make(chan error, 1)
+	ctx, g := New(context.Background(), Collect, WithOnError(func(info TaskInfo, err error) {
+		if info.Index == -1 {
+			reported <- err
+		}
+	}))
+
+	stopped := make(chan struct{})
+	g.GoDetached(ctx, func() error {
+		defer close(stopped)
+		<-ctx.Done()
+		return nil
+	})
+	g.GoDetached(ctx, func() error {
+		panic(errInternal)
+	})
+	g.Go(ctx, func() error {
+		return nil
+	})
+
+	var panicErr *PanicError
+	if err := <-reported; !errors.As(err, &panicErr) || !errors.Is(err, errInternal) {
+		t.Errorf("expected the panic of the detached goroutine to be reported, but got %v", err)
+	}
+	waitFor(t, func() bool { return g.Stats().Detached == 1 })
+
+	// Wait does not wait for the detached goroutine, but cancels it.
+	if err := g.Wait(); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+	<-stopped
+	waitFor(t, func() bool { return g.Stats().Detached == 0 })
+}
+
+func TestGroup_GoDetached_IgnoreErrors(t *testing.T) {
+	reported := make(chan error, 2)
+	ctx, g := New(context.Background(), Collect,
+		WithIgnoreErrors(func(err error) bool {
+			return errors.Is(err, errInvalid)
+		}),
+		WithErrorMapper(func(_ TaskInfo, err error) error {
+			return fmt.Errorf("mapped: %w", err)
+		}),
+		WithOnError(func(_ TaskInfo, err error) {
+			reported <- err
+		}),
+	)
+
+	g.GoDetached(ctx, func() error {
+		return errInvalid
+	})
+	g.GoDetached(ctx, func() error {
+		return errInternal
+	})
+
+	if err := <-reported; err.Error() != "mapped: "+errInternal.Error() {
+		t.Errorf("expected the mapped error to be reported, but got %v", err)
+	}
+	waitFor(t, func() bool { return g.Stats().Detached == 0 })
+	select {
+	case err := <-reported:
+		t.Errorf("expected the ignored error not to be reported, but got %v", err)
+	default:
+	}
+	g.Wait()
+}
@@ -0,0 +1,64 @@
+package workgroup
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// WithTaskStacks allows DumpTasks to capture the stack traces of the
+// running goroutines of the workgroup. It is disabled by default, as it
+// adds to the cost of starting every goroutine.
+func WithTaskStacks() Option {
+	return func(g *Group) {
+		g.stacks = true
+	}
+}
+
+// RunningTasks describes the goroutines of the workgroup currently
+// executing, in order of submission. It is safe to call at any time.
+func (g *Group) RunningTasks() []TaskInfo {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.activeTasks()
+}
+
+// DumpTasks writes a description of every running goroutine of the
+// workgroup to w, in order of submission: its name or index, its current
+// attempt, when it started and, with WithTaskStacks, its stack trace.
+// When a workgroup hangs in production, it shows what the goroutines are
+// stuck on, e.g. from a debug endpoint or a signal handler.
+func (g *Group) DumpTasks(w io.Writer) error {
+	now := g.clock().Now()
+	g.errLock.Lock()
+	tasks := make([]*task, 0, len(g.active))
+	for t := range g.active {
+		tasks = append(tasks, t)
+	}
+	g.errLock.Unlock()
+	slices.SortFunc(tasks, func(a, b *task) int {
+		return a.index - b.index
+	})
+
+	var stacks map[uint64][]byte
+	if g.stacks {
+		stacks = goroutineStacks()
+	}
+	for _, t := range tasks {
+		info := t.info()
+		name := fmt.Sprintf("task %d", info.Index)
+		if info.Name != "" {
+			name = fmt.Sprintf("task %q", info.Name)
+		}
+		_, err := fmt.Fprintf(w, "%s, attempt %d, running since %s (%v)\n",
+			name, info.Attempts, t.start.Format(time.RFC3339), now.Sub(t.start))
+		if err == nil && stacks != nil {
+			_, err = fmt.Fprintf(w, "%s\n\n", stacks[t.goroutine])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package workgroup
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGroup_DumpTasks(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithTaskStacks())
+
+	release := make(chan struct{})
+	g.GoNamed(ctx, "stuck", func() error {
+		<-release
+		return nil
+	})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+	waitFor(t, func() bool { return g.Running() == 2 })
+
+	var buf bytes.Buffer
+	if err := g.DumpTasks(&buf); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	g.Wait()
+
+	dump := buf.String()
+	for _, want := range []string{`task "stuck", attempt 1, running since `, "task 1, attempt 1, ", "TestGroup_DumpTasks"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("expected the dump to contain %q, but got:\n%s", want, dump)
+		}
+	}
+	if i, j := strings.Index(dump, `task "stuck"`), strings.Index(dump, "task 1,"); i > j {
+		t.Errorf("expected the goroutines in order of submission, but got:\n%s", dump)
+	}
+}
+
+func TestGroup_DumpTasks_NoStacks(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	release := make(chan struct{})
+	g.GoNamed(ctx, "stuck", func() error {
+		<-release
+		return nil
+	})
+	waitFor(t, func() bool { return g.Running() == 1 })
+
+	var buf bytes.Buffer
+	if err := g.DumpTasks(&buf); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	g.Wait()
+
+	dump := buf.String()
+	if !strings.HasPrefix(dump, `task "stuck", attempt 1, `) || strings.Count(dump, "\n") != 1 {
+		t.Errorf("expected a single line without stack trace, but got:\n%s", dump)
+	}
+}
@@ -0,0 +1,107 @@
+package workgroup
+
+import "sync"
+
+// ErrCh returns a channel that delivers the error of every failing task
+// as it occurs, while the rest of the workgroup keeps running. The errors
+// recorded before the first call to ErrCh are delivered first, as far as
+// they are retained by the workgroup, see Errors. The channel is closed
+// once Wait has returned and all errors have been delivered.
+//
+// Unlike Errors, the channel delivers the errors of all tasks failing
+// after the first call, including those that are not retained in
+// FailFast mode or because of WithMaxErrors. Ignored errors are not
+// delivered. Errors are buffered internally, so a slow consumer never
+// blocks the tasks of the workgroup.
+//
+// Once Wait has returned, the errors that have not been received yet are
+// only delivered to a receiver that is ready, and dropped otherwise, so
+// that an abandoned channel does not leak the goroutine delivering them.
+// They are still reported by Wait.
+func (g *Group) ErrCh() <-chan error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	if g.errStream == nil {
+		g.errStream = newErrStream(g.errs)
+		if g.finished {
+			g.errStream.close()
+		}
+	}
+	return g.errStream.ch
+}
+
+// errStream delivers errors on a channel through an unbounded buffer.
+type errStream struct {
+	ch chan error
+	// done is closed once the workgroup has finished.
+	done chan struct{}
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []error
+	closed bool
+}
+
+// newErrStream creates an errStream delivering the errors recorded so
+// far first. They are buffered by the channel itself, so that they are
+// not dropped if the workgroup has already finished.
+func newErrStream(recorded []error) *errStream {
+	s := &errStream{
+		ch:   make(chan error, len(recorded)),
+		done: make(chan struct{}),
+	}
+	for _, err := range recorded {
+		s.ch <- err
+	}
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s
+}
+
+// push queues err for delivery.
+func (s *errStream) push(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, err)
+	s.cond.Signal()
+}
+
+// close closes the channel once all queued errors have been delivered or
+// dropped, see ErrCh.
+func (s *errStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.done)
+	s.cond.Signal()
+}
+
+func (s *errStream) run() {
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			close(s.ch)
+			return
+		}
+		err := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- err:
+			continue
+		case <-s.done:
+		}
+		// The workgroup has finished: deliver err only to a receiver that
+		// is ready.
+		select {
+		case s.ch <- err:
+		default:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroup_ErrCh(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithMaxErrors(1), WithIgnoreErrors(func(err error) bool {
+		return errors.Is(err, errInvalid)
+	}))
+	errCh := g.ErrCh()
+
+	g.Go(ctx, func() error {
+		return errInvalid
+	})
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			return errInternal
+		})
+	}
+	// All failures are delivered, even those omitted by WithMaxErrors.
+	for i := 0; i < 5; i++ {
+		if err := <-errCh; !errors.Is(err, errInternal) {
+			t.Errorf("ErrCh delivered %v, want %v", err, errInternal)
+		}
+	}
+	g.Wait()
+
+	if err, ok := <-errCh; ok {
+		t.Errorf("ErrCh delivered %v after Wait, want a closed channel", err)
+	}
+}
+
+func TestGroup_ErrCh_Replay(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	g.Go(ctx, func() error { return errInternal })
+	waitFor(t, func() bool { return len(g.Errors()) == 1 })
+
+	errCh := g.ErrCh()
+	g.Go(ctx, func() error { return errInvalid })
+	g.Wait()
+
+	// The error recorded before the first call is delivered first, and
+	// the channel is closed although nobody was receiving at the end of
+	// the workgroup.
+	if err := <-errCh; !errors.Is(err, errInternal) {
+		t.Errorf("ErrCh delivered %v, want %v", err, errInternal)
+	}
+	waitFor(t, func() bool {
+		select {
+		case _, ok := <-errCh:
+			return !ok
+		default:
+			return false
+		}
+	})
+}
+
+func TestGroup_ErrCh_AfterWait(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	g.Go(ctx, func() error { return errInternal })
+	g.Wait()
+
+	errCh := g.ErrCh()
+	if err := <-errCh; !errors.Is(err, errInternal) {
+		t.Errorf("ErrCh after Wait delivered %v, want %v", err, errInternal)
+	}
+	if _, ok := <-errCh; ok {
+		t.Error("ErrCh after Wait delivered more than the recorded errors, want a closed channel")
+	}
+}
@@ -0,0 +1,123 @@
+// Package errgroup provides the API of golang.org/x/sync/errgroup backed
+// by a workgroup, so that existing code can migrate by only changing the
+// import path:
+//
+//	import "github.com/sadlil/workgroup/errgroup"
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.SetLimit(10)
+//	for _, url := range urls {
+//		g.Go(func() error {
+//			return fetch(ctx, url)
+//		})
+//	}
+//	if err := g.Wait(); err != nil {
+//		return err
+//	}
+//
+// As with errgroup, every function passed to Go runs, even once the
+// context is canceled, and Wait returns the first error as is. Unlike
+// errgroup, a panic in a function is re-raised by Wait as a
+// *workgroup.PanicError.
+package errgroup
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sadlil/workgroup"
+)
+
+// Group is a collection of goroutines working on subtasks of a common
+// task. A zero Group is valid, has no limit on the number of active
+// goroutines, and does not cancel on error.
+type Group struct {
+	cancel func(error)
+
+	init sync.Once
+	wg   *workgroup.Group
+
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a new Group and an associated context derived from
+// ctx. The derived context is canceled the first time a function passed
+// to Go returns a non-nil error, or the first time Wait returns,
+// whichever occurs first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// group returns the workgroup running the goroutines of g. It runs them in
+// the Collect mode, which does not skip functions once an error occurred.
+func (g *Group) group() *workgroup.Group {
+	g.init.Do(func() {
+		_, g.wg = workgroup.New(context.Background(), workgroup.Collect,
+			workgroup.WithPanicPolicy(workgroup.PanicRepanic))
+	})
+	return g.wg
+}
+
+// Go calls the given function in a new goroutine. It blocks until the new
+// goroutine can be added without the number of active goroutines in the
+// group exceeding the configured limit.
+//
+// The first call to return a non-nil error cancels the group's context,
+// if the group was created by calling WithContext. The error will be
+// returned by Wait.
+func (g *Group) Go(f func() error) {
+	g.group().Go(context.Background(), g.wrap(f))
+}
+
+// TryGo calls the given function in a new goroutine only if the number of
+// active goroutines in the group is currently below the configured limit.
+//
+// The return value reports whether the goroutine was started.
+func (g *Group) TryGo(f func() error) bool {
+	return g.group().TryGo(context.Background(), g.wrap(f))
+}
+
+// SetLimit limits the number of active goroutines in this group to at
+// most n. A negative value indicates no limit. A limit of zero will
+// prevent any new goroutines from being added.
+//
+// Unlike with errgroup, the limit may be changed while goroutines are
+// active, see workgroup.Group.SetLimit.
+func (g *Group) SetLimit(n int) {
+	g.group().SetLimit(n)
+}
+
+// Wait blocks until all function calls from the Go method have returned,
+// then returns the first non-nil error (if any) from them.
+//
+// As with errgroup, Go may be called again once Wait has returned, and
+// the first error is kept across the rounds.
+func (g *Group) Wait() error {
+	wg := g.group()
+	wg.Wait()
+	// The context of the workgroup is canceled by Wait, which would skip
+	// the functions passed to Go afterwards.
+	wg.Reset()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+// wrap returns f, recording its first error as the error of the group.
+func (g *Group) wrap(f func() error) func() error {
+	return func() error {
+		err := f()
+		if err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+		return err
+	}
+}
@@ -0,0 +1,140 @@
+package errgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sadlil/workgroup"
+)
+
+var (
+	errInternal = errors.New("internal")
+	errInvalid  = errors.New("invalid")
+)
+
+func TestGroup_ZeroValue(t *testing.T) {
+	var (
+		g   Group
+		ran atomic.Int32
+	)
+	g.Go(func() error {
+		ran.Add(1)
+		return errInternal
+	})
+	g.Go(func() error {
+		ran.Add(1)
+		return nil
+	})
+
+	if err := g.Wait(); err != errInternal {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if n := ran.Load(); n != 2 {
+		t.Errorf("expected 2 functions to run, but got %d", n)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+
+	failed := make(chan struct{})
+	g.Go(func() error {
+		defer close(failed)
+		return errInvalid
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return errInternal
+	})
+	<-failed
+	// Functions still run once the context is canceled.
+	var ran bool
+	g.Go(func() error {
+		ran = true
+		return nil
+	})
+
+	if err := g.Wait(); err != errInvalid {
+		t.Errorf("group.Wait() = %v, want %v", err, errInvalid)
+	}
+	if !ran {
+		t.Error("expected the function launched after the failure to run")
+	}
+	if cause := context.Cause(ctx); cause != errInvalid {
+		t.Errorf("context.Cause(ctx) = %v, want %v", cause, errInvalid)
+	}
+}
+
+func TestWithContext_WaitCancels(t *testing.T) {
+	g, ctx := WithContext(context.Background())
+	g.Go(func() error {
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("expected Wait to cancel the context")
+	}
+}
+
+func TestGroup_WaitTwice(t *testing.T) {
+	var g Group
+	g.Go(func() error {
+		return errInvalid
+	})
+	if err := g.Wait(); err != errInvalid {
+		t.Errorf("group.Wait() = %v, want %v", err, errInvalid)
+	}
+
+	var ran bool
+	g.Go(func() error {
+		ran = true
+		return errInternal
+	})
+	if err := g.Wait(); err != errInvalid {
+		t.Errorf("group.Wait() = %v, want %v", err, errInvalid)
+	}
+	if !ran {
+		t.Error("expected the function launched after Wait to run")
+	}
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+
+	block := make(chan struct{})
+	if !g.TryGo(func() error {
+		<-block
+		return nil
+	}) {
+		t.Fatal("expected TryGo to start the first goroutine")
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Error("expected TryGo not to exceed the limit")
+	}
+	close(block)
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+}
+
+func TestGroup_Panic(t *testing.T) {
+	var g Group
+	g.Go(func() error {
+		panic("boom")
+	})
+
+	defer func() {
+		if panicErr, ok := recover().(*workgroup.PanicError); !ok || panicErr.Value != "boom" {
+			t.Errorf("expected Wait to panic with the *PanicError of the goroutine, but got %v", panicErr)
+		}
+	}()
+	g.Wait()
+	t.Error("expected Wait to panic")
+}
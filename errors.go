@@ -0,0 +1,285 @@
+package workgroup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// TaskInfo describes a task of a workgroup.
+type TaskInfo struct {
+	// ID is a random identifier of the task, unique for every
+	// submission. It is available from the context of the task, see
+	// TaskID.
+	ID string
+	// Index is the submission index of the task within the workgroup,
+	// starting at 0. It is -1 until the task is launched, see
+	// Listener.OnSubmit, and for detached tasks, see GoDetached.
+	Index int
+	// Name is the name of the task, set with the Name task option or
+	// GoNamed, or empty.
+	Name string
+	// Attempts is the number of times the task was executed so far,
+	// including retries.
+	Attempts int
+}
+
+// TaskError is the error of a single task of a workgroup. It annotates
+// the error returned by the task with information about the task, while
+// still supporting errors.Is and errors.As on the underlying error.
+//
+// Every error collected by a Group, and returned from Wait or Errors, is a
+// *TaskError.
+type TaskError struct {
+	TaskInfo
+	// Duration is the time elapsed from the start of the first attempt
+	// until the task finished.
+	Duration time.Duration
+	// Err is the error returned by the task.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TaskError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("task %q: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error returned by the task.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// taskErrorJSON is the JSON representation of a TaskError.
+type taskErrorJSON struct {
+	ID       string `json:"id,omitempty"`
+	Index    int    `json:"index"`
+	Name     string `json:"name,omitempty"`
+	Attempts int    `json:"attempts"`
+	Duration string `json:"duration"`
+	Error    string `json:"error"`
+}
+
+// MarshalJSON implements json.Marshaler. The task's duration is encoded
+// in the format of time.Duration.String.
+func (e *TaskError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(taskErrorJSON{
+		ID:       e.ID,
+		Index:    e.Index,
+		Name:     e.Name,
+		Attempts: e.Attempts,
+		Duration: e.Duration.String(),
+		Error:    e.Err.Error(),
+	})
+}
+
+// PanicError is the error of a task that panicked. It allows callers to
+// tell crashes apart from regular task errors with errors.As:
+//
+//	var panicErr *workgroup.PanicError
+//	if errors.As(err, &panicErr) {
+//		log.Printf("task crashed: %v\n%s", panicErr.Value, panicErr.Stack)
+//	}
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the
+	// panic.
+	Stack []byte
+}
+
+// newPanicError captures the stack of the current goroutine, which must
+// be the one recovering from the panic.
+func newPanicError(v any) *PanicError {
+	return &PanicError{Value: v, Stack: debug.Stack()}
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is
+// and errors.As match errors passed to panic.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ShutdownError is the error returned from Group.Shutdown when goroutines
+// did not finish in time and had to be canceled.
+type ShutdownError struct {
+	// Killed describes the goroutines that were still running when the
+	// workgroup was canceled.
+	Killed []TaskInfo
+	// Err is the error of the workgroup, as returned from Wait.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ShutdownError) Error() string {
+	msg := fmt.Sprintf("workgroup: shutdown canceled %d running goroutines", len(e.Killed))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the error of the workgroup.
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// AggregateError is the error returned from Group.Wait when goroutines of
+// the workgroup failed. It holds the individual errors of the failed
+// tasks along with a summary of the workgroup's execution.
+//
+// AggregateError implements Unwrap() []error, so errors.Is and errors.As
+// match any of the individual errors.
+type AggregateError struct {
+	// Errors holds the collected errors, in the order they occurred.
+	// Each error is a *TaskError.
+	Errors []error
+	// Omitted is the number of errors that were not collected because of
+	// WithMaxErrors or WithErrorSampling.
+	Omitted int
+	// Succeeded is the number of tasks that succeeded.
+	Succeeded int
+	// Failed is the number of tasks that failed, including those whose
+	// errors were omitted.
+	Failed int
+	// Classes holds the number of failed tasks per error class, if a
+	// classifier was set with WithErrorClassifier.
+	Classes map[string]int
+	// Duration is the time elapsed from the creation of the workgroup
+	// until Wait returned.
+	Duration time.Duration
+}
+
+// Error implements the error interface. Like errors.Join, it returns the
+// messages of the individual errors separated by newlines.
+func (e *AggregateError) Error() string {
+	var b strings.Builder
+	for i, err := range e.Errors {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+	if e.Omitted > 0 {
+		if len(e.Errors) > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "and %d more errors", e.Omitted)
+	}
+	return b.String()
+}
+
+// Unwrap returns the collected errors.
+func (e *AggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// TaskErrors returns the collected errors as *TaskError, giving access
+// to the metadata of each failed task.
+func (e *AggregateError) TaskErrors() []*TaskError {
+	errs := make([]*TaskError, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if te, ok := err.(*TaskError); ok {
+			errs = append(errs, te)
+		}
+	}
+	return errs
+}
+
+// MarshalJSON implements json.Marshaler, producing a machine-readable
+// report of the workgroup's failures suitable for API responses and
+// structured logs:
+//
+//	{
+//	  "succeeded": 8,
+//	  "failed": 2,
+//	  "omitted": 0,
+//	  "duration": "1.5s",
+//	  "classes": {"timeout": 2},
+//	  "errors": [
+//	    {"index": 3, "name": "sync-users", "attempts": 1, "duration": "1.2s", "error": "..."},
+//	    ...
+//	  ]
+//	}
+func (e *AggregateError) MarshalJSON() ([]byte, error) {
+	errs := make([]json.RawMessage, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		var (
+			b       []byte
+			jsonErr error
+		)
+		if te, ok := err.(*TaskError); ok {
+			b, jsonErr = te.MarshalJSON()
+		} else {
+			b, jsonErr = json.Marshal(struct {
+				Error string `json:"error"`
+			}{Error: err.Error()})
+		}
+		if jsonErr != nil {
+			return nil, jsonErr
+		}
+		errs = append(errs, b)
+	}
+
+	return json.Marshal(struct {
+		Succeeded int               `json:"succeeded"`
+		Failed    int               `json:"failed"`
+		Omitted   int               `json:"omitted"`
+		Duration  string            `json:"duration"`
+		Classes   map[string]int    `json:"classes,omitempty"`
+		Errors    []json.RawMessage `json:"errors"`
+	}{
+		Succeeded: e.Succeeded,
+		Failed:    e.Failed,
+		Omitted:   e.Omitted,
+		Duration:  e.Duration.String(),
+		Classes:   e.Classes,
+		Errors:    errs,
+	})
+}
+
+// SignalError is the cause of the cancellation of a workgroup shut down by
+// a signal, see WithShutdownSignals.
+type SignalError struct {
+	// Signal is the last signal received.
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+func (e *SignalError) Error() string {
+	return "workgroup: received signal " + e.Signal.String()
+}
+
+// DeadlineError is returned from Group.Wait, joined with the errors of the
+// goroutines, when the deadline of the workgroup was exceeded while
+// goroutines were still running, see WithDeadline. It matches
+// context.DeadlineExceeded with errors.Is.
+type DeadlineError struct {
+	// Running describes the goroutines that were still running at the
+	// deadline.
+	Running []TaskInfo
+}
+
+// Error implements the error interface.
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("workgroup: deadline exceeded with %d running goroutines", len(e.Running))
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *DeadlineError) Unwrap() error {
+	return context.DeadlineExceeded
+}
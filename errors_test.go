@@ -0,0 +1,155 @@
+package workgroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTaskError(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1), WithRetry(Attempts(3), Delay(time.Millisecond)))
+	g.Go(ctx, func() error {
+		return nil
+	})
+	g.GoNamed(ctx, "sync-users", func() error {
+		time.Sleep(5 * time.Millisecond)
+		return fmt.Errorf("sync: %w", errInternal)
+	})
+	g.Wait()
+
+	errs := g.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("group.Errors() returned %d errors, want 1", len(errs))
+	}
+
+	var taskErr *TaskError
+	if !errors.As(errs[0], &taskErr) {
+		t.Fatalf("errors.As(err, *TaskError) = false, want true")
+	}
+	if !errors.Is(taskErr, errInternal) {
+		t.Errorf("errors.Is(taskErr, errInternal) = false, want true")
+	}
+	if taskErr.Index != 1 {
+		t.Errorf("taskErr.Index = %d, want 1", taskErr.Index)
+	}
+	if taskErr.Name != "sync-users" {
+		t.Errorf("taskErr.Name = %q, want %q", taskErr.Name, "sync-users")
+	}
+	if taskErr.Attempts != 3 {
+		t.Errorf("taskErr.Attempts = %d, want 3", taskErr.Attempts)
+	}
+	if taskErr.Duration < 15*time.Millisecond {
+		t.Errorf("taskErr.Duration = %v, want at least 15ms", taskErr.Duration)
+	}
+	if got, want := taskErr.Error(), `task "sync-users": sync: internal`; got != want {
+		t.Errorf("taskErr.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAggregateError(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	g.GoNamed(ctx, "a", func() error { return errInternal })
+	g.GoNamed(ctx, "b", func() error { return errInvalid })
+	g.GoNamed(ctx, "c", func() error { return nil })
+
+	err := g.Wait()
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		t.Fatalf("errors.As(err, *AggregateError) = false, want true")
+	}
+	if !errors.Is(err, errInternal) || !errors.Is(err, errInvalid) {
+		t.Errorf("group.Wait() = %v, want both %v and %v", err, errInternal, errInvalid)
+	}
+	if aggErr.Succeeded != 1 || aggErr.Failed != 2 {
+		t.Errorf("aggErr succeeded/failed = %d/%d, want 1/2", aggErr.Succeeded, aggErr.Failed)
+	}
+	if aggErr.Duration <= 0 {
+		t.Errorf("aggErr.Duration = %v, want > 0", aggErr.Duration)
+	}
+	var again *AggregateError
+	if !errors.As(g.Wait(), &again) || again.Duration != aggErr.Duration {
+		t.Errorf("expected the duration to be fixed once the workgroup finished, but got %v", again)
+	}
+
+	names := map[string]bool{}
+	for _, te := range aggErr.TaskErrors() {
+		names[te.Name] = true
+	}
+	if len(names) != 2 || !names["a"] || !names["b"] {
+		t.Errorf("aggErr.TaskErrors() names = %v, want a and b", names)
+	}
+}
+
+func TestAggregateError_MarshalJSON(t *testing.T) {
+	err := &AggregateError{
+		Errors: []error{
+			&TaskError{
+				TaskInfo: TaskInfo{Index: 3, Name: "sync-users", Attempts: 2},
+				Duration: 1500 * time.Millisecond,
+				Err:      errInternal,
+			},
+			errInvalid,
+		},
+		Omitted:   1,
+		Succeeded: 7,
+		Failed:    3,
+		Duration:  2 * time.Second,
+	}
+
+	got, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", jsonErr)
+	}
+	want := `{"succeeded":7,"failed":3,"omitted":1,"duration":"2s","errors":[` +
+		`{"index":3,"name":"sync-users","attempts":2,"duration":"1.5s","error":"internal"},` +
+		`{"error":"invalid"}]}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithRetry(Attempts(3), Delay(time.Millisecond)))
+
+	var attempts int
+	g.Go(ctx, func() error {
+		attempts++
+		panic("boom")
+	})
+	g.Go(ctx, func() error {
+		panic(fmt.Errorf("crash: %w", errInternal))
+	})
+	g.Go(ctx, func() error {
+		return errInvalid
+	})
+
+	err := g.Wait()
+	if attempts != 1 {
+		t.Errorf("expected panicking task to run once, but got %d attempts", attempts)
+	}
+	if !errors.Is(err, errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+
+	var panics int
+	for _, err := range g.Errors() {
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) {
+			if !errors.Is(err, errInvalid) {
+				t.Errorf("unexpected error %v", err)
+			}
+			continue
+		}
+		panics++
+		if !strings.Contains(string(panicErr.Stack), "TestPanicError") {
+			t.Errorf("panicErr.Stack does not contain the panicking function:\n%s", panicErr.Stack)
+		}
+	}
+	if panics != 2 {
+		t.Errorf("expected 2 panic errors, but got %d", panics)
+	}
+}
@@ -0,0 +1,50 @@
+package workgroup
+
+import (
+	"context"
+	"time"
+)
+
+// GoEvery launches a goroutine within the workgroup running fn every
+// interval, like a time.Ticker, until the context of the workgroup is
+// done, e.g. for a background refresh loop stopped along with the
+// workgroup. fn first runs once interval has elapsed, and receives the
+// context of the task, see GoCtx. Ticks missed while fn runs are
+// skipped.
+//
+// The goroutine counts as a single goroutine towards the concurrency
+// limit and for Wait, which therefore only returns once the workgroup is
+// canceled, e.g. with Cancel or by a failure in the FailFast mode. It
+// ends with the error of fn if fn fails, and is not retried unless opts
+// set a retry policy with TaskRetry. GoEvery panics if interval is not
+// positive, like time.NewTicker.
+func (g *Group) GoEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error, opts ...TaskOption) {
+	if interval <= 0 {
+		panic("workgroup: non-positive interval for GoEvery")
+	}
+	clock := g.clock()
+	opts = append([]TaskOption{TaskRetry(Attempts(1))}, opts...)
+	t := g.newTaskContext(func(ctx context.Context) error {
+		next := clock.Now()
+		for {
+			now := clock.Now()
+			next = next.Add(interval)
+			if missed := now.Sub(next); missed >= 0 {
+				next = next.Add((missed/interval + 1) * interval)
+			}
+			timer := clock.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C():
+			case <-ctx.Done():
+				timer.Stop()
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err := fn(ctx); err != nil {
+				return err
+			}
+		}
+	}, opts)
+	g.launch(ctx, t)
+}
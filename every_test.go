@@ -0,0 +1,59 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_GoEvery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	ctx, g := New(context.Background(), Collect, WithClock(clock))
+
+	var ticks []time.Duration
+	g.GoEvery(ctx, time.Minute, func(context.Context) error {
+		ticks = append(ticks, clock.Now().Sub(start))
+		if len(ticks) == 2 {
+			// Misses the next tick.
+			clock.advance(90 * time.Second)
+		}
+		if len(ticks) == 4 {
+			g.Cancel()
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	if len(ticks) != len(want) {
+		t.Fatalf("expected fn to run at %v, but got %v", want, ticks)
+	}
+	for i := range want {
+		if ticks[i] != want[i] {
+			t.Errorf("expected fn to run at %v, but got %v", want, ticks)
+			break
+		}
+	}
+}
+
+func TestGroup_GoEvery_Error(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithClock(&fakeClock{}))
+
+	var runs int
+	g.GoEvery(ctx, time.Second, func(context.Context) error {
+		runs++
+		return errInternal
+	}, Name("refresh"))
+
+	var taskErr *TaskError
+	if err := g.Wait(); !errors.As(err, &taskErr) || taskErr.Name != "refresh" || !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want the refresh goroutine to fail with %v", err, errInternal)
+	}
+	if runs != 1 {
+		t.Errorf("expected fn to run once, but got %d runs", runs)
+	}
+}
@@ -0,0 +1,61 @@
+package workgroup
+
+import "context"
+
+// Result holds the outcome of a task launched with Bind.
+//
+// The value and error are only meaningful after the Wait method of the
+// Group the task was bound to has returned.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Value returns the value produced by the task. It is the zero value
+// of T if the task failed.
+func (r *Result[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error returned by the task, or nil if it succeeded.
+func (r *Result[T]) Err() error {
+	return r.err
+}
+
+// Get returns both the value and the error produced by the task.
+func (r *Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Bind launches fn as a new task within the workgroup and returns a
+// Result that holds its typed value once g.Wait returns.
+//
+// Bind makes it possible to scatter differently-typed subtasks across a
+// single Group and gather all of their results after Wait, without
+// resorting to interface{} or capturing result variables in closures:
+//
+//	ctx, g := workgroup.New(ctx, workgroup.FailFast)
+//	user := workgroup.Bind(ctx, g, func() (*User, error) { return fetchUser(ctx, id) })
+//	orders := workgroup.Bind(ctx, g, func() ([]Order, error) { return fetchOrders(ctx, id) })
+//	if err := g.Wait(); err != nil {
+//		return err
+//	}
+//	render(user.Value(), orders.Value())
+//
+// The task is subject to the same failure mode, retry policy and
+// concurrency limit as tasks launched with Go. When the task is retried,
+// the Result holds the outcome of the last attempt. Task options
+// configure the task like for Go.
+func Bind[T any](ctx context.Context, g *Group, fn func() (T, error), opts ...TaskOption) *Result[T] {
+	r := &Result[T]{}
+	g.Go(ctx, func() error {
+		var zero T
+		v, err := fn()
+		if err != nil {
+			v = zero
+		}
+		r.value, r.err = v, err
+		return err
+	}, opts...)
+	return r
+}
@@ -0,0 +1,53 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBind(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	name := Bind(ctx, g, func() (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "gopher", nil
+	})
+	age := Bind(ctx, g, func() (int, error) {
+		return 14, nil
+	})
+	failed := Bind(ctx, g, func() ([]byte, error) {
+		return []byte("partial"), fmt.Errorf("read: %w", errInternal)
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Fatalf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if got, err := name.Get(); got != "gopher" || err != nil {
+		t.Errorf("name.Get() = (%q, %v), want (%q, nil)", got, err, "gopher")
+	}
+	if got := age.Value(); got != 14 {
+		t.Errorf("age.Value() = %d, want 14", got)
+	}
+	if got := failed.Value(); got != nil {
+		t.Errorf("failed.Value() = %q, want nil", got)
+	}
+	if !errors.Is(failed.Err(), errInternal) {
+		t.Errorf("failed.Err() = %v, want %v", failed.Err(), errInternal)
+	}
+}
+
+func TestBind_TaskOptions(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	Bind(ctx, g, func() (int, error) {
+		return 0, errInternal
+	}, Name("fetch"))
+
+	var taskErr *TaskError
+	if err := g.Wait(); !errors.As(err, &taskErr) || taskErr.Name != "fetch" {
+		t.Errorf("group.Wait() = %v, want the error of the task named fetch", err)
+	}
+}
@@ -1,5 +1,3 @@
 module github.com/sadlil/workgroup
 
 go 1.23.1
-
-require github.com/avast/retry-go v3.0.0+incompatible // indirect
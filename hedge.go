@@ -0,0 +1,67 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// GoHedged launches a new goroutine within the workgroup that executes fn
+// as a hedged request, a common technique to cut tail latency.
+//
+// If the first attempt of fn has not finished within delay, a duplicate
+// attempt is started concurrently. The result of whichever attempt
+// finishes first is used as the result of the task, and the context
+// passed to the other attempt is canceled. Wait also waits for the
+// canceled attempt to return.
+//
+// The hedged task counts as a single goroutine towards the concurrency
+// limit and is retried as a whole according to the workgroup's retry
+// policy.
+//
+// Like GoCtx, both attempts are passed contexts derived from the context
+// of the task, and carrying its values, see TaskID.
+func (g *Group) GoHedged(ctx context.Context, delay time.Duration, fn func(ctx context.Context) error, opts ...TaskOption) {
+	var t *task
+	t = g.newTaskContext(func(ctx context.Context) error {
+		return g.hedge(ctx, t, delay, fn)
+	}, opts)
+	g.launch(ctx, t)
+}
+
+func (g *Group) hedge(ctx context.Context, t *task, delay time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// Cancel the losing attempt once the winner is known.
+	defer cancel()
+
+	results := make(chan error, 2)
+	attempt := func() {
+		g.enter()
+		go func() {
+			defer g.leave()
+			// Reported as is if fn calls runtime.Goexit.
+			err := Permanent(ErrGoexit)
+			defer func() {
+				results <- err
+			}()
+			err = g.safeCall(ctx, t, fn)
+			var panicErr *PanicError
+			if errors.As(err, &panicErr) {
+				// Like panics of tasks, never retried.
+				err = Permanent(err)
+			}
+		}()
+	}
+
+	attempt()
+	timer := g.clock().NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case err := <-results:
+		return err
+	case <-timer.C():
+		attempt()
+	}
+	return <-results
+}
@@ -0,0 +1,107 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_GoHedged(t *testing.T) {
+	var (
+		attempts int32
+		canceled int32
+	)
+
+	ctx, g := New(context.Background(), Collect)
+	start := time.Now()
+	g.GoHedged(ctx, 10*time.Millisecond, func(ctx context.Context) error {
+		// The first attempt is slow, the hedged one is fast.
+		d := 20 * time.Millisecond
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			d = time.Second
+		}
+		select {
+		case <-time.After(d):
+			return nil
+		case <-ctx.Done():
+			atomic.AddInt32(&canceled, 1)
+			return ctx.Err()
+		}
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("group.Wait() took %v, want the hedged attempt to win", elapsed)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, but got %d", attempts)
+	}
+	if canceled != 1 {
+		t.Errorf("expected the losing attempt to be canceled, but got %d cancellations", canceled)
+	}
+}
+
+func TestGroup_GoHedged_TaskContext(t *testing.T) {
+	_, g := New(context.Background(), Collect)
+
+	ids := make(chan string, 2)
+	started := make(chan struct{}, 2)
+	// Submitted with a context unrelated to the workgroup.
+	g.GoHedged(context.Background(), time.Millisecond, func(ctx context.Context) error {
+		id, _ := TaskID(ctx)
+		ids <- id
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}, Name("lookup"))
+
+	<-started
+	<-started
+	g.Cancel()
+	if err := g.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("group.Wait() = %v, want %v", err, context.Canceled)
+	}
+	first, second := <-ids, <-ids
+	if first == "" || first != second {
+		t.Errorf("expected both attempts to see the ID of the task, but got %q and %q", first, second)
+	}
+}
+
+func TestGroup_GoHedged_NoHedge(t *testing.T) {
+	var attempts int32
+
+	ctx, g := New(context.Background(), Collect)
+	g.GoHedged(ctx, time.Second, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, but got %d", attempts)
+	}
+}
+
+func TestGroup_GoHedged_Panic(t *testing.T) {
+	var attempts int32
+
+	ctx, g := New(context.Background(), Collect, WithRetry(Attempts(3), Delay(time.Millisecond)))
+	g.GoHedged(ctx, time.Second, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		panic("boom")
+	})
+
+	var panicErr *PanicError
+	if err := g.Wait(); !errors.As(err, &panicErr) {
+		t.Fatalf("group.Wait() = %v, want a *PanicError", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected the panicking attempt not to be retried, but got %d attempts", attempts)
+	}
+}
@@ -0,0 +1,83 @@
+package workgroup
+
+import (
+	"context"
+	"time"
+)
+
+// Hook observes the execution of the goroutines of a workgroup, see
+// WithHook. Unlike the callbacks of the workgroup, it can derive the
+// context of every goroutine, which allows to instrument the goroutines
+// e.g. with tracing spans, like the otelworkgroup module, without this
+// package depending on a tracing library. Methods are called
+// synchronously from the goroutines of the workgroup, possibly
+// concurrently, so they must be safe for concurrent use and must not
+// block.
+//
+// Implementations should embed NoopHook, so that they keep compiling when
+// methods are added to the interface.
+type Hook interface {
+	// Start is called when a goroutine starts executing, with the context
+	// it was submitted with and the context of the goroutine, which is
+	// derived from the context of the workgroup. The returned context
+	// replaces the latter, and is passed to Retry and Finish.
+	Start(submit, ctx context.Context, info TaskInfo) context.Context
+	// Retry is called when a failed attempt of a goroutine is about to be
+	// retried after delay, with the error of the failed attempt.
+	Retry(ctx context.Context, info TaskInfo, err error, delay time.Duration)
+	// Finish is called when a goroutine has finished, including all
+	// retries, with its duration and its error as collected by the
+	// workgroup, which is nil if it succeeded or its error was ignored.
+	Finish(ctx context.Context, info TaskInfo, duration time.Duration, err error)
+}
+
+// NoopHook is a Hook implementation that does nothing.
+type NoopHook struct{}
+
+// Start implements Hook.
+func (NoopHook) Start(_, ctx context.Context, _ TaskInfo) context.Context {
+	return ctx
+}
+
+// Retry implements Hook.
+func (NoopHook) Retry(context.Context, TaskInfo, error, time.Duration) {}
+
+// Finish implements Hook.
+func (NoopHook) Finish(context.Context, TaskInfo, time.Duration, error) {}
+
+// WithHook adds a Hook observing the execution of the goroutines of the
+// workgroup. Several hooks can be added, e.g. to trace the goroutines and
+// to export metrics; each one derives the context of the goroutines from
+// the one returned by the previous hook.
+func WithHook(h Hook) Option {
+	return func(g *Group) {
+		g.hooks = append(g.hooks, h)
+	}
+}
+
+// startHooks calls the Start method of the hooks for t, and returns the
+// context of t derived by them from ctx.
+func (g *Group) startHooks(ctx context.Context, t *task) context.Context {
+	submit := t.submit
+	if submit == nil {
+		submit = context.Background()
+	}
+	for _, h := range g.hooks {
+		ctx = h.Start(submit, ctx, t.info())
+	}
+	return ctx
+}
+
+// retryHooks calls the Retry method of the hooks for t.
+func (g *Group) retryHooks(ctx context.Context, t *task, err error, delay time.Duration) {
+	for _, h := range g.hooks {
+		h.Retry(ctx, t.info(), err, delay)
+	}
+}
+
+// finishHooks calls the Finish method of the hooks for t.
+func (g *Group) finishHooks(ctx context.Context, t *task, err error) {
+	for _, h := range g.hooks {
+		h.Finish(ctx, t.info(), t.duration, err)
+	}
+}
@@ -0,0 +1,83 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type hookKey struct{}
+
+// recordingHook records the calls of a Hook, checking that Retry and
+// Finish receive the context returned by Start.
+type recordingHook struct {
+	t  *testing.T
+	mu sync.Mutex
+	// events are the recorded calls, e.g. "start flaky submit".
+	events []string
+}
+
+func (h *recordingHook) record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *recordingHook) Start(submit, ctx context.Context, info TaskInfo) context.Context {
+	h.record(fmt.Sprintf("start %s %v", info.Name, submit.Value(hookKey{})))
+	return context.WithValue(ctx, hookKey{}, info.Name)
+}
+
+func (h *recordingHook) Retry(ctx context.Context, info TaskInfo, err error, _ time.Duration) {
+	h.record(fmt.Sprintf("retry %s %v", ctx.Value(hookKey{}), info.Attempts))
+}
+
+func (h *recordingHook) Finish(ctx context.Context, info TaskInfo, duration time.Duration, err error) {
+	if duration <= 0 {
+		h.t.Errorf("expected the duration of %s, but got %v", info.Name, duration)
+	}
+	h.record(fmt.Sprintf("finish %s %v", ctx.Value(hookKey{}), errors.Is(err, errInternal)))
+}
+
+func TestGroup_WithHook(t *testing.T) {
+	hook := &recordingHook{t: t}
+	ctx, g := New(context.Background(), Collect,
+		WithRetry(Attempts(2), Delay(time.Millisecond)),
+		WithHook(NoopHook{}),
+		WithHook(hook),
+	)
+
+	var attempts int
+	g.GoNamed(context.WithValue(ctx, hookKey{}, "submit"), "flaky", func() error {
+		time.Sleep(time.Millisecond)
+		attempts++
+		if attempts == 1 {
+			return errInternal
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	g.Reset()
+	g.GoNamed(context.Background(), "failed", func() error {
+		time.Sleep(time.Millisecond)
+		return Permanent(errInternal)
+	})
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Fatalf("group.Wait() = %v, want %v", err, errInternal)
+	}
+
+	want := []string{
+		"start flaky submit", "retry flaky 1", "finish flaky false",
+		"start failed <nil>", "finish failed true",
+	}
+	if !slices.Equal(hook.events, want) {
+		t.Errorf("expected the calls %q, but got %q", want, hook.events)
+	}
+}
@@ -0,0 +1,146 @@
+// Package httpdebug serves the live state of workgroups over HTTP, for
+// long-lived worker services. Like net/http/pprof, its handler is meant
+// to be mounted under /debug:
+//
+//	ctx, g := workgroup.New(ctx, workgroup.Collect, workgroup.WithName("ingest"))
+//	httpdebug.Register(g)
+//	http.Handle("/debug/workgroups/", httpdebug.Handler())
+//
+// The handler responds with a JSON document listing the registered
+// workgroups, with their stats, running goroutines and most recent
+// errors. With the query parameter name, it only lists the workgroups
+// with that name, and with stacks=1, it responds instead with the stack
+// traces of their running goroutines, see workgroup.Group.DumpTasks,
+// which are only captured for workgroups created with
+// workgroup.WithTaskStacks.
+package httpdebug
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sadlil/workgroup"
+)
+
+// maxErrors is the number of most recent errors listed per workgroup.
+const maxErrors = 10
+
+var (
+	mu     sync.Mutex
+	groups = make(map[*workgroup.Group]struct{})
+)
+
+// Register registers g to be listed by the handler until it finishes, see
+// workgroup.Group.Defer, or until the returned function is called.
+func Register(g *workgroup.Group) (unregister func()) {
+	mu.Lock()
+	groups[g] = struct{}{}
+	mu.Unlock()
+
+	unregister = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(groups, g)
+	}
+	g.Defer(unregister)
+	return unregister
+}
+
+// registered returns the registered workgroups, ordered by name.
+func registered() []*workgroup.Group {
+	mu.Lock()
+	defer mu.Unlock()
+	gs := make([]*workgroup.Group, 0, len(groups))
+	for g := range groups {
+		gs = append(gs, g)
+	}
+	slices.SortStableFunc(gs, func(a, b *workgroup.Group) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+	return gs
+}
+
+// group is the JSON representation of a workgroup.
+type group struct {
+	Name    string               `json:"name"`
+	Stats   stats                `json:"stats"`
+	Running []workgroup.TaskInfo `json:"running"`
+	Errors  []string             `json:"errors"`
+}
+
+// stats is the JSON representation of workgroup.Stats.
+type stats struct {
+	Submitted int    `json:"submitted"`
+	Pending   int    `json:"pending"`
+	Running   int    `json:"running"`
+	Detached  int    `json:"detached"`
+	Completed int    `json:"completed"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Ignored   int    `json:"ignored"`
+	Retried   int    `json:"retried"`
+	Elapsed   string `json:"elapsed"`
+}
+
+// Handler returns an http.Handler serving the state of the registered
+// workgroups.
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	var gs []*workgroup.Group
+	name, filter := r.URL.Query().Get("name"), r.URL.Query().Has("name")
+	for _, g := range registered() {
+		if !filter || g.Name() == name {
+			gs = append(gs, g)
+		}
+	}
+
+	if r.URL.Query().Get("stacks") == "1" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, g := range gs {
+			if _, err := w.Write([]byte("workgroup " + g.Name() + "\n\n")); err != nil {
+				return
+			}
+			if err := g.DumpTasks(w); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	resp := make([]group, 0, len(gs))
+	for _, g := range gs {
+		s := g.Stats()
+		errs := g.Errors()
+		errs = errs[max(len(errs)-maxErrors, 0):]
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		resp = append(resp, group{
+			Name: g.Name(),
+			Stats: stats{
+				Submitted: s.Submitted,
+				Pending:   s.Pending,
+				Running:   s.Running,
+				Detached:  s.Detached,
+				Completed: s.Completed,
+				Succeeded: s.Succeeded,
+				Failed:    s.Failed,
+				Ignored:   s.Ignored,
+				Retried:   s.Retried,
+				Elapsed:   s.Elapsed.Round(time.Millisecond).String(),
+			},
+			Running: g.RunningTasks(),
+			Errors:  msgs,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
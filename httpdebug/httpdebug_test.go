@@ -0,0 +1,60 @@
+package httpdebug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sadlil/workgroup"
+)
+
+var errInternal = errors.New("internal")
+
+func TestHandler(t *testing.T) {
+	ctx, g := workgroup.New(context.Background(), workgroup.Collect, workgroup.WithName("ingest"))
+	Register(g)
+
+	release := make(chan struct{})
+	g.GoNamed(ctx, "stuck", func() error {
+		<-release
+		return nil
+	})
+	g.GoNamed(ctx, "failing", func() error {
+		return errInternal
+	})
+	for g.Stats().Failed == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/workgroups/", nil))
+	var resp []group
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp) != 1 || resp[0].Name != "ingest" {
+		t.Fatalf("expected the registered workgroup to be listed, but got %+v", resp)
+	}
+	if got := resp[0]; got.Stats.Running != 1 || len(got.Running) != 1 || got.Running[0].Name != "stuck" {
+		t.Errorf("expected the stuck goroutine to be listed as running, but got %+v", got)
+	}
+	if got := resp[0].Errors; len(got) != 1 || got[0] != `task "failing": internal` {
+		t.Errorf("expected the error of the failing goroutine, but got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/workgroups/?name=ingest&stacks=1", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `task "stuck", attempt 1`) {
+		t.Errorf("expected the stack traces of the running goroutines, but got:\n%s", body)
+	}
+
+	close(release)
+	g.Wait()
+	if gs := registered(); len(gs) != 0 {
+		t.Errorf("expected the workgroup to be unregistered once finished, but got %d", len(gs))
+	}
+}
@@ -0,0 +1,69 @@
+package workgroup
+
+import "context"
+
+// GoKeyed is like Go, but guarantees that goroutines launched with the
+// same key never execute concurrently, while goroutines with different
+// keys run in parallel. It serializes e.g. the work on a single tenant or
+// resource within a large fan-out.
+//
+// If a goroutine with the same key is running, fn is queued without
+// blocking the caller, and launched once all previous goroutines with
+// that key have finished, in order of submission.
+func (g *Group) GoKeyed(ctx context.Context, key string, fn func() error, opts ...TaskOption) {
+	t := g.newTask(fn, opts)
+	t.key = &key
+	t.submit = ctx
+	// Notified before the task can be queued, so that it precedes its
+	// start by the task holding the key.
+	g.emitSubmit(t)
+
+	// The key is reserved in the same critical section as the check, so
+	// that the task holding it is not rejected by a concurrent Close or
+	// Drain, which would leave the tasks queued behind it waiting forever.
+	g.errLock.Lock()
+	if err := g.acceptingLocked(); err != nil {
+		g.errLock.Unlock()
+		g.reject(t, err)
+		return
+	}
+	if g.keys == nil {
+		g.keys = make(map[string][]*task)
+	}
+	if queue, busy := g.keys[key]; busy {
+		g.keys[key] = append(queue, t)
+		g.pending.Add(1)
+		// Keep Wait waiting for the queued task.
+		g.enterLocked()
+		g.errLock.Unlock()
+		return
+	}
+	g.keys[key] = nil
+	g.errLock.Unlock()
+
+	g.spawn(t)
+}
+
+// next launches the task queued after t for the key of t, if any, or
+// marks the key as idle.
+func (g *Group) next(t *task) {
+	g.errLock.Lock()
+	queue := g.keys[*t.key]
+	if len(queue) == 0 {
+		delete(g.keys, *t.key)
+		g.errLock.Unlock()
+		return
+	}
+	next := queue[0]
+	g.keys[*t.key] = queue[1:]
+	g.errLock.Unlock()
+
+	// Wait for admission in a new goroutine, as the current one may be a
+	// worker of the pool needed by the tasks holding the slots.
+	go func() {
+		defer g.leave()
+		release := g.add(next)
+		g.pending.Add(-1)
+		g.run(next, release)
+	}()
+}
@@ -0,0 +1,90 @@
+package workgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup_GoKeyed(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	var (
+		mu      sync.Mutex
+		running = make(map[string]int)
+		order   = make(map[string][]int)
+		overlap bool
+		maxKeys int
+	)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i%3)
+		g.GoKeyed(ctx, key, func() error {
+			mu.Lock()
+			running[key]++
+			if running[key] > 1 {
+				overlap = true
+			}
+			maxKeys = max(maxKeys, len(running))
+			order[key] = append(order[key], i)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			if running[key]--; running[key] == 0 {
+				delete(running, key)
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	if overlap {
+		t.Error("expected goroutines with the same key not to run concurrently")
+	}
+	if maxKeys != 2 {
+		t.Errorf("expected goroutines with different keys to run in parallel up to the limit, but got %d", maxKeys)
+	}
+	for key, o := range order {
+		for j := 1; j < len(o); j++ {
+			if o[j] < o[j-1] {
+				t.Errorf("expected the goroutines of %s to run in order, but got %v", key, o)
+				break
+			}
+		}
+	}
+}
+
+func TestGroup_GoKeyed_Close(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		ctx, g := New(context.Background(), Collect)
+		var wg sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				g.GoKeyed(ctx, "key", func() error {
+					return nil
+				})
+			}()
+		}
+		g.Close()
+		wg.Wait()
+
+		done := make(chan struct{})
+		go func() {
+			g.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected Wait to return when closing the workgroup while submitting keyed goroutines")
+		}
+	}
+}
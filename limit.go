@@ -0,0 +1,272 @@
+package workgroup
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SetLimit changes the maximum number of goroutines that can execute
+// concurrently within the workgroup, while goroutines may be running. A
+// negative value removes the limit.
+//
+// Raising the limit immediately admits goroutines blocked in Go. Lowering
+// it does not interrupt running goroutines: new goroutines are only
+// admitted once enough of the running ones have finished to get below
+// the new limit.
+func (g *Group) SetLimit(n int) {
+	g.admission().setLimit(n)
+}
+
+// Pause stops admitting new goroutines into execution until Resume is
+// called. Running goroutines, including their retries, are not affected.
+// Go blocks while the workgroup is paused, like when the concurrency
+// limit is reached, and Submit queues the submitted functions. It allows
+// to temporarily quiesce a workgroup without canceling it.
+func (g *Group) Pause() {
+	g.admission().setPaused(true)
+}
+
+// Resume resumes admitting goroutines into execution after Pause, in
+// the scheduling order.
+func (g *Group) Resume() {
+	g.admission().setPaused(false)
+}
+
+// admission returns the limiter of the concurrency limit, creating an
+// unlimited one if the workgroup has none.
+func (g *Group) admission() *limiter {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	if g.limiter == nil {
+		g.limiter = newLimiter(-1)
+		g.limiter.order = g.order
+	}
+	return g.limiter
+}
+
+// WithLimitPerCPU sets the maximum number of goroutines that can execute
+// concurrently within the workgroup to n times runtime.GOMAXPROCS(0). The
+// limit follows changes of GOMAXPROCS, e.g. by the Go runtime adjusting
+// to the CPU quota of a container, as goroutines are admitted. It avoids
+// hard-coding machine-specific limits for CPU-bound work.
+func WithLimitPerCPU(n int) Option {
+	return func(g *Group) {
+		g.limiter = newLimiter(n)
+		g.limiter.perCPU = true
+	}
+}
+
+// WithWeightedLimit sets the total weight of the goroutines that can
+// execute concurrently within the workgroup, where the weight of each
+// goroutine is set with the Weight task option. It models workloads of
+// mixed costs, e.g. small and huge file uploads, better than WithLimit,
+// which it complements: a goroutine must fit within both limits to run.
+func WithWeightedLimit(capacity int) Option {
+	return func(g *Group) {
+		g.weighted = newLimiter(capacity)
+	}
+}
+
+// WithTagLimit limits the number of goroutines with the given tag, see
+// Tag, that can execute concurrently within the workgroup to n, in
+// addition to the limits of the workgroup. It implements the bulkhead
+// pattern, e.g. at most 100 goroutines in total but at most 10 hitting
+// the database, without nesting workgroups.
+func WithTagLimit(tag string, n int) Option {
+	return func(g *Group) {
+		if g.tagLimits == nil {
+			g.tagLimits = make(map[string]*limiter)
+		}
+		g.tagLimits[tag] = newLimiter(n)
+	}
+}
+
+// SchedulingOrder defines the order in which pending tasks of the same
+// priority are admitted once the concurrency limits allow it.
+type SchedulingOrder int
+
+const (
+	// FIFO admits pending tasks in order of submission. It is the
+	// default.
+	FIFO SchedulingOrder = iota
+	// LIFO admits the most recently submitted pending tasks first, which
+	// improves cache locality for some workloads, at the cost of
+	// fairness.
+	LIFO
+	// EDF admits the pending tasks with the earliest deadline first, see
+	// TaskDeadline. Tasks without a deadline are admitted last, in order
+	// of submission.
+	EDF
+)
+
+// WithScheduling sets the order in which pending tasks are admitted.
+func WithScheduling(order SchedulingOrder) Option {
+	return func(g *Group) {
+		g.order = order
+	}
+}
+
+// limiter is a weighted semaphore bounding the goroutines executing
+// concurrently. The goroutines waiting for a slot are admitted by
+// priority, then in the scheduling order.
+type limiter struct {
+	order SchedulingOrder
+
+	mu     sync.Mutex
+	limit  int  // Negative for no limit.
+	perCPU bool // Whether limit is per CPU.
+	paused bool
+	// memoryLimit is the heap usage above which nothing is admitted, see
+	// WithMemoryLimit, and polling whether it is being polled.
+	memoryLimit uint64
+	polling     bool
+	ramp        *rampUp
+	startLimit  *startLimit
+	used        int
+	waiters     []*waiter
+}
+
+// waiter is a goroutine blocked in acquire.
+type waiter struct {
+	n        int
+	priority int
+	deadline time.Time
+	ready    chan struct{}
+}
+
+func newLimiter(n int) *limiter {
+	return &limiter{limit: n}
+}
+
+// acquire blocks until w.n is available and takes it, or until ctx is
+// done, in which case it returns the cause of ctx.
+func (l *limiter) acquire(ctx context.Context, w *waiter) error {
+	n := w.n
+	l.mu.Lock()
+	if len(l.waiters) == 0 && l.available(n) {
+		l.take(n)
+		l.mu.Unlock()
+		return nil
+	}
+	w.ready = make(chan struct{})
+	l.waiters = append(l.waiters, w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-w.ready:
+		// Admitted concurrently with ctx being done.
+		l.used -= n
+	default:
+		for i, o := range l.waiters {
+			if o == w {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+	// The waiters behind w may fit now.
+	l.admit()
+	return context.Cause(ctx)
+}
+
+// tryAcquire takes n if it is available without waiting, and reports
+// whether it did.
+func (l *limiter) tryAcquire(n int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.waiters) > 0 || !l.available(n) {
+		return false
+	}
+	l.take(n)
+	return true
+}
+
+// release frees n taken by acquire.
+func (l *limiter) release(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.used -= n
+	l.admit()
+}
+
+func (l *limiter) setLimit(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = n
+	l.perCPU = false
+	l.admit()
+}
+
+func (l *limiter) setPaused(paused bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.paused = paused
+	l.admit()
+}
+
+// available reports whether n can be taken. n larger than the limit can
+// be taken once nothing else is, rather than never.
+func (l *limiter) available(n int) bool {
+	if l.paused || l.overMemory() || !l.startAllowed() {
+		return false
+	}
+	limit := l.limit
+	if l.perCPU {
+		limit *= runtime.GOMAXPROCS(0)
+	}
+	limit = l.rampLimit(limit)
+	return limit < 0 || l.used+n <= limit || (l.used == 0 && limit > 0)
+}
+
+// admit hands the available capacity to the waiters, in order. It stops
+// at the first waiter that does not fit, so that heavy waiters are not
+// starved by lighter ones. l.mu must be held.
+func (l *limiter) admit() {
+	for len(l.waiters) > 0 {
+		i := l.next()
+		w := l.waiters[i]
+		if !l.available(w.n) {
+			return
+		}
+		l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+		l.take(w.n)
+		close(w.ready)
+	}
+}
+
+// next returns the index of the next waiter to admit, the first one
+// according to before. l.mu must be held.
+func (l *limiter) next() int {
+	next := 0
+	for i, w := range l.waiters[1:] {
+		if l.before(w, l.waiters[next]) {
+			next = i + 1
+		}
+	}
+	return next
+}
+
+// before reports whether w, which arrived after o, must be admitted
+// before o.
+func (l *limiter) before(w, o *waiter) bool {
+	if w.priority != o.priority {
+		return w.priority > o.priority
+	}
+	switch l.order {
+	case LIFO:
+		return true
+	case EDF:
+		return !w.deadline.IsZero() && (o.deadline.IsZero() || w.deadline.Before(o.deadline))
+	}
+	return false
+}
@@ -0,0 +1,360 @@
+package workgroup
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+
+	var (
+		running  atomic.Int32
+		shrunk   atomic.Bool
+		exceeded atomic.Bool
+	)
+	release := make(chan struct{})
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		for i := 0; i < 6; i++ {
+			g.Go(ctx, func() error {
+				if c := running.Add(1); shrunk.Load() && c > 1 {
+					exceeded.Store(true)
+				}
+				<-release
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}
+	}()
+
+	waitFor(t, func() bool { return running.Load() == 1 })
+	time.Sleep(10 * time.Millisecond)
+	if c := running.Load(); c != 1 {
+		t.Fatalf("expected 1 running goroutine, but got %d", c)
+	}
+
+	g.SetLimit(3)
+	waitFor(t, func() bool { return running.Load() == 3 })
+
+	g.SetLimit(1)
+	shrunk.Store(true)
+	close(release)
+	<-submitted
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if exceeded.Load() {
+		t.Error("expected the lowered limit to be respected")
+	}
+}
+
+func TestGroup_SetLimit_ZeroValue(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+
+	var (
+		current int32
+		max     int32
+	)
+	for i := 0; i < 10; i++ {
+		g.Go(context.Background(), func() error {
+			c := atomic.AddInt32(&current, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if c <= m || atomic.CompareAndSwapInt32(&max, m, c) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&current, -1)
+			return nil
+		})
+	}
+	g.Wait()
+
+	if max != 2 {
+		t.Errorf("expected maximum 2 concurrent goroutines, but got %d", max)
+	}
+}
+
+func TestGroup_WithWeightedLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithWeightedLimit(10))
+
+	var (
+		mu       sync.Mutex
+		current  int
+		exceeded bool
+	)
+	run := func(weight int) {
+		g.Go(ctx, func() error {
+			mu.Lock()
+			current += weight
+			// The heavy goroutine may only exceed the capacity alone.
+			if current > 10 && current != weight {
+				exceeded = true
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			current -= weight
+			mu.Unlock()
+			return nil
+		}, Weight(weight))
+	}
+	for i := 0; i < 5; i++ {
+		run(1)
+		run(4)
+	}
+	run(20) // Heavier than the capacity, runs alone.
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	if exceeded {
+		t.Error("expected the weighted limit to be respected")
+	}
+}
+
+func TestGroup_WithLimit_FIFO(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	var producers sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			g.Go(ctx, func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, i)
+				return nil
+			})
+		}()
+		// Wait for the producer to be blocked before starting the next.
+		waitFor(t, func() bool {
+			g.limiter.mu.Lock()
+			defer g.limiter.mu.Unlock()
+			return len(g.limiter.waiters) == i+1
+		})
+	}
+	close(release)
+	producers.Wait()
+	g.Wait()
+
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("expected the producers to be admitted in order, but got %v", order)
+		}
+	}
+}
+
+func TestGroup_WithScheduling_LIFO(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1), WithScheduling(LIFO))
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	for i := 0; i < 5; i++ {
+		g.Submit(ctx, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+			return nil
+		})
+		// Wait for the task to be pending before submitting the next.
+		waitFor(t, func() bool {
+			g.limiter.mu.Lock()
+			defer g.limiter.mu.Unlock()
+			return len(g.limiter.waiters) == i+1
+		})
+	}
+	close(release)
+	g.Wait()
+
+	if want := []int{4, 3, 2, 1, 0}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected the tasks to run in order %v, but got %v", want, order)
+	}
+}
+
+func TestGroup_WithScheduling_EDF(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1), WithScheduling(EDF))
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	now := time.Now()
+	deadlines := []time.Time{now.Add(3 * time.Hour), {}, now.Add(time.Hour), now.Add(2 * time.Hour)}
+	for i, d := range deadlines {
+		g.Submit(ctx, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+			return nil
+		}, TaskDeadline(d))
+		waitFor(t, func() bool {
+			g.limiter.mu.Lock()
+			defer g.limiter.mu.Unlock()
+			return len(g.limiter.waiters) == i+1
+		})
+	}
+	close(release)
+	g.Wait()
+
+	if want := []int{2, 3, 0, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected the tasks to run in order %v, but got %v", want, order)
+	}
+}
+
+func TestGroup_WithLimitPerCPU(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+
+	ctx, g := New(context.Background(), Collect, WithLimitPerCPU(2))
+	if !g.TryGo(ctx, func() error { return nil }) {
+		t.Fatal("TryGo() = false with a free slot, want true")
+	}
+	g.Wait()
+
+	g.limiter.mu.Lock()
+	defer g.limiter.mu.Unlock()
+	for _, tc := range []struct {
+		procs, used int
+		want        bool
+	}{
+		{procs: 2, used: 3, want: true},
+		{procs: 2, used: 4, want: false},
+		{procs: 4, used: 7, want: true},
+		{procs: 4, used: 8, want: false},
+	} {
+		runtime.GOMAXPROCS(tc.procs)
+		g.limiter.used = tc.used
+		if got := g.limiter.available(1); got != tc.want {
+			t.Errorf("available() with GOMAXPROCS=%d and %d running = %v, want %v", tc.procs, tc.used, got, tc.want)
+		}
+	}
+	g.limiter.used = 0
+}
+
+func TestGroup_Pause(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	release := make(chan struct{})
+	var started atomic.Int32
+	g.Go(ctx, func() error {
+		started.Add(1)
+		<-release
+		return nil
+	})
+	waitFor(t, func() bool { return started.Load() == 1 })
+
+	g.Pause()
+	for i := 0; i < 3; i++ {
+		g.Submit(ctx, func() error {
+			started.Add(1)
+			return nil
+		})
+	}
+	time.Sleep(10 * time.Millisecond)
+	if s := started.Load(); s != 1 {
+		t.Errorf("expected no goroutine to start while paused, but got %d", s-1)
+	}
+	if g.TryGo(ctx, func() error { return nil }) {
+		t.Error("TryGo() = true while paused, want false")
+	}
+
+	g.Resume()
+	waitFor(t, func() bool { return started.Load() == 4 })
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+}
+
+func TestGroup_WithTagLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(4), WithTagLimit("db", 1))
+
+	var (
+		mu       sync.Mutex
+		running  = make(map[string]int)
+		maxSeen  = make(map[string]int)
+		maxTotal int
+	)
+	run := func(tag string, opts ...TaskOption) {
+		g.Submit(ctx, func() error {
+			mu.Lock()
+			running[tag]++
+			maxSeen[tag] = max(maxSeen[tag], running[tag])
+			maxTotal = max(maxTotal, running["db"]+running["other"])
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			running[tag]--
+			mu.Unlock()
+			return nil
+		}, opts...)
+	}
+	for i := 0; i < 5; i++ {
+		run("db", Tag("db"))
+		run("other")
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	if maxSeen["db"] != 1 {
+		t.Errorf("expected at most 1 concurrent db goroutine, but got %d", maxSeen["db"])
+	}
+	if maxTotal > 4 {
+		t.Errorf("expected at most 4 concurrent goroutines, but got %d", maxTotal)
+	}
+	if maxSeen["other"] < 2 {
+		t.Errorf("expected untagged goroutines not to be held back by the db limit, but got %d", maxSeen["other"])
+	}
+}
@@ -0,0 +1,87 @@
+package workgroup
+
+import (
+	"errors"
+	"time"
+)
+
+// Listener is notified of the lifecycle events of the goroutines of a
+// workgroup, see WithListener. It is a single extension point for
+// logging, tracing or metrics. Methods are called synchronously from the
+// goroutines of the workgroup, possibly concurrently, so they must be
+// safe for concurrent use and must not block.
+//
+// Implementations should embed NoopListener, so that they keep compiling
+// when methods are added to the interface.
+type Listener interface {
+	// OnSubmit is called when a goroutine is submitted, before it waits
+	// for admission by the concurrency limits. Its index is only assigned
+	// once it is launched, so the Index of info is -1. No further event
+	// follows if the goroutine is never launched, e.g. because the
+	// context passed to GoErr is done while waiting.
+	OnSubmit(info TaskInfo)
+	// OnStart is called when a goroutine starts executing, with its
+	// index assigned.
+	OnStart(info TaskInfo)
+	// OnRetry is called when a failed attempt of a goroutine is about to
+	// be retried after delay.
+	OnRetry(info TaskInfo, err error, delay time.Duration)
+	// OnPanic is called when a goroutine has panicked, before OnFinish.
+	OnPanic(info TaskInfo, err *PanicError)
+	// OnFinish is called when a goroutine has finished, including all
+	// retries, with its error as collected by the workgroup, which is nil
+	// if it succeeded or its error was ignored.
+	OnFinish(info TaskInfo, err error)
+}
+
+// NoopListener is a Listener ignoring all events.
+type NoopListener struct{}
+
+// OnSubmit implements Listener.
+func (NoopListener) OnSubmit(TaskInfo) {}
+
+// OnStart implements Listener.
+func (NoopListener) OnStart(TaskInfo) {}
+
+// OnRetry implements Listener.
+func (NoopListener) OnRetry(TaskInfo, error, time.Duration) {}
+
+// OnPanic implements Listener.
+func (NoopListener) OnPanic(TaskInfo, *PanicError) {}
+
+// OnFinish implements Listener.
+func (NoopListener) OnFinish(TaskInfo, error) {}
+
+// WithListener registers a Listener notified of the lifecycle events of
+// the goroutines of the workgroup. Several listeners can be registered,
+// and are notified in order of registration.
+func WithListener(l Listener) Option {
+	return func(g *Group) {
+		g.listeners = append(g.listeners, l)
+	}
+}
+
+// emit notifies the listeners of the workgroup.
+func (g *Group) emit(event func(l Listener)) {
+	for _, l := range g.listeners {
+		event(l)
+	}
+}
+
+// emitSubmit notifies the listeners that t was submitted.
+func (g *Group) emitSubmit(t *task) {
+	g.emit(func(l Listener) { l.OnSubmit(t.info()) })
+}
+
+// emitFinish notifies the listeners that t has finished with err.
+func (g *Group) emitFinish(t *task, err error) {
+	if len(g.listeners) == 0 {
+		return
+	}
+	info := t.info()
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		g.emit(func(l Listener) { l.OnPanic(info, panicErr) })
+	}
+	g.emit(func(l Listener) { l.OnFinish(info, err) })
+}
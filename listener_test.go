@@ -0,0 +1,69 @@
+package workgroup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingListener is a Listener recording the events of the goroutines.
+type recordingListener struct {
+	NoopListener
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *recordingListener) record(event string, info TaskInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, fmt.Sprintf("%s %s %d %d", event, info.Name, info.Index, info.Attempts))
+}
+
+func (l *recordingListener) OnSubmit(info TaskInfo) { l.record("submit", info) }
+
+func (l *recordingListener) OnStart(info TaskInfo) { l.record("start", info) }
+
+func (l *recordingListener) OnRetry(info TaskInfo, _ error, _ time.Duration) {
+	l.record("retry", info)
+}
+
+func (l *recordingListener) OnPanic(info TaskInfo, _ *PanicError) { l.record("panic", info) }
+
+func (l *recordingListener) OnFinish(info TaskInfo, _ error) { l.record("finish", info) }
+
+func TestGroup_WithListener(t *testing.T) {
+	l := &recordingListener{}
+	ctx, g := New(context.Background(), Collect,
+		WithListener(l),
+		WithRetry(Attempts(3), Delay(time.Millisecond)),
+		WithLimit(1),
+	)
+
+	var attempts int
+	g.GoNamed(ctx, "flaky", func() error {
+		attempts++
+		if attempts < 2 {
+			return errInternal
+		}
+		return nil
+	})
+	g.GoNamed(ctx, "crash", func() error {
+		panic("boom")
+	})
+	g.Wait()
+
+	// The crash goroutine is submitted while the flaky one holds the only
+	// slot, and only gets its index once launched.
+	want := []string{
+		"submit flaky -1 0", "submit crash -1 0",
+		"start flaky 0 0", "retry flaky 0 1", "finish flaky 0 2",
+		"start crash 1 0", "panic crash 1 1", "finish crash 1 1",
+	}
+	if !slices.Equal(l.events, want) {
+		t.Errorf("expected the events %q, but got %q", want, l.events)
+	}
+}
@@ -0,0 +1,92 @@
+package workgroup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogLevels sets the levels at which the events of the goroutines of a
+// workgroup are logged, see WithLogger and WithLogLevels.
+type LogLevels struct {
+	// Start is the level of goroutines starting to execute.
+	Start slog.Level
+	// Finish is the level of goroutines that succeeded.
+	Finish slog.Level
+	// Retry is the level of failed attempts about to be retried.
+	Retry slog.Level
+	// Failure is the level of goroutines that failed.
+	Failure slog.Level
+}
+
+// defaultLogLevels are the LogLevels used by WithLogger.
+var defaultLogLevels = LogLevels{
+	Start:   slog.LevelDebug,
+	Finish:  slog.LevelDebug,
+	Retry:   slog.LevelWarn,
+	Failure: slog.LevelError,
+}
+
+// WithLogger logs the start, retries, success and failure of every
+// goroutine of the workgroup to logger, with the name or index of the
+// task, its attempts, its duration and its error as attributes, instead
+// of logging in every function. The levels of the events are set with
+// WithLogLevels.
+func WithLogger(logger *slog.Logger) Option {
+	return func(g *Group) {
+		if g.logger == nil {
+			g.logger = &logMetrics{levels: defaultLogLevels}
+		}
+		g.logger.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels at which WithLogger logs the events of
+// the goroutines of the workgroup. Without it, starts and successes are
+// logged at slog.LevelDebug, retries at slog.LevelWarn and failures at
+// slog.LevelError.
+func WithLogLevels(levels LogLevels) Option {
+	return func(g *Group) {
+		if g.logger == nil {
+			g.logger = &logMetrics{}
+		}
+		g.logger.levels = levels
+	}
+}
+
+// logMetrics is a Metrics logging the events of goroutines.
+type logMetrics struct {
+	logger *slog.Logger
+	levels LogLevels
+}
+
+func (l *logMetrics) TaskStarted(info TaskInfo) {
+	l.log(l.levels.Start, "task started", info)
+}
+
+func (l *logMetrics) TaskFinished(info TaskInfo, duration time.Duration, err error) {
+	if err != nil {
+		l.log(l.levels.Failure, "task failed", info,
+			slog.Duration("duration", duration),
+			slog.Any("error", err))
+		return
+	}
+	l.log(l.levels.Finish, "task finished", info, slog.Duration("duration", duration))
+}
+
+func (l *logMetrics) RetryAttempted(info TaskInfo, err error) {
+	l.log(l.levels.Retry, "task retrying", info, slog.Any("error", err))
+}
+
+func (l *logMetrics) log(level slog.Level, msg string, info TaskInfo, attrs ...slog.Attr) {
+	ctx := context.Background()
+	if l.logger == nil || !l.logger.Enabled(ctx, level) {
+		return
+	}
+	task := slog.Int("task", info.Index)
+	if info.Name != "" {
+		task = slog.String("task", info.Name)
+	}
+	attrs = append([]slog.Attr{task, slog.String("task_id", info.ID), slog.Int("attempt", info.Attempts)}, attrs...)
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,59 @@
+package workgroup
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" || a.Key == "task_id" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	ctx, g := New(context.Background(), Collect,
+		WithLogger(logger),
+		WithRetry(Attempts(2), Delay(time.Millisecond)),
+	)
+
+	g.GoNamed(ctx, "failing", func() error {
+		return errInternal
+	})
+	g.Wait()
+
+	want := []string{
+		`level=WARN msg="task retrying" task=failing attempt=1 error=internal`,
+		`level=ERROR msg="task failed" task=failing attempt=2 error="task \"failing\": internal"`,
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected logs:\n%s\nbut got:\n%s", strings.Join(want, "\n"), buf.String())
+	}
+}
+
+func TestGroup_WithLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ctx, g := New(context.Background(), Collect,
+		WithLogger(logger),
+		WithLogLevels(LogLevels{Start: slog.LevelInfo, Finish: slog.LevelInfo}),
+	)
+
+	g.Go(ctx, func() error {
+		return nil
+	})
+	g.Wait()
+
+	for _, msg := range []string{`msg="task started" task=0`, `msg="task finished" task=0`} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("expected the logs to contain %s, but got:\n%s", msg, buf.String())
+		}
+	}
+}
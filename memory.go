@@ -0,0 +1,63 @@
+package workgroup
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// memoryPollInterval is the interval at which the heap usage is polled
+// while goroutines wait for it to drop below the limit set with
+// WithMemoryLimit.
+const memoryPollInterval = 100 * time.Millisecond
+
+// WithMemoryLimit stops admitting new goroutines into execution while the
+// heap usage of the process, as reported by runtime/metrics, exceeds
+// bytes, and resumes once it drops below. Running goroutines are not
+// affected. It prevents large fan-outs with big per-task payloads from
+// running out of memory.
+func WithMemoryLimit(bytes uint64) Option {
+	return func(g *Group) {
+		g.memoryLimit = bytes
+	}
+}
+
+// heapBytes returns the heap memory occupied by objects. It is a variable
+// for testing.
+var heapBytes = func() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// overMemory reports whether the heap usage exceeds the memory limit, in
+// which case it starts polling for it to drop. l.mu must be held.
+func (l *limiter) overMemory() bool {
+	if l.memoryLimit == 0 || heapBytes() <= l.memoryLimit {
+		return false
+	}
+	if !l.polling {
+		l.polling = true
+		go l.pollMemory()
+	}
+	return true
+}
+
+// pollMemory admits the waiters once the heap usage drops below the
+// memory limit.
+func (l *limiter) pollMemory() {
+	ticker := time.NewTicker(memoryPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.mu.Lock()
+		l.admit()
+		if len(l.waiters) == 0 || heapBytes() <= l.memoryLimit {
+			l.polling = false
+			l.mu.Unlock()
+			return
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,43 @@
+package workgroup
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithMemoryLimit(t *testing.T) {
+	var heap atomic.Uint64
+	heap.Store(2 << 20)
+	defer func(fn func() uint64) { heapBytes = fn }(heapBytes)
+	heapBytes = heap.Load
+
+	ctx, g := New(context.Background(), Collect, WithMemoryLimit(1<<20))
+
+	var count atomic.Int32
+	for i := 0; i < 3; i++ {
+		g.Submit(ctx, func() error {
+			count.Add(1)
+			return nil
+		})
+	}
+	time.Sleep(2 * memoryPollInterval)
+	if c := count.Load(); c != 0 {
+		t.Errorf("expected no goroutine to start above the memory limit, but got %d", c)
+	}
+
+	heap.Store(1 << 19)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if c := count.Load(); c != 3 {
+		t.Errorf("expected 3 goroutines to run below the memory limit, but got %d", c)
+	}
+}
+
+func TestHeapBytes(t *testing.T) {
+	if heapBytes() == 0 {
+		t.Error("heapBytes() = 0, want the heap usage")
+	}
+}
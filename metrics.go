@@ -0,0 +1,87 @@
+package workgroup
+
+import "time"
+
+// Metrics receives measurements of the goroutines of a workgroup, see
+// WithMetrics. It allows to bind the workgroup to any metrics backend,
+// e.g. statsd, OpenTelemetry or expvar, without this package depending on
+// one. Methods are called synchronously from the goroutines of the
+// workgroup, possibly concurrently, so they must be safe for concurrent
+// use and must not block.
+//
+// Implementations should embed NoopMetrics, so that they keep compiling
+// when methods are added to the interface.
+type Metrics interface {
+	// TaskStarted is called when a goroutine starts executing, after it
+	// was admitted by the concurrency limits.
+	TaskStarted(info TaskInfo)
+	// TaskFinished is called when a goroutine has finished, including
+	// all retries, with its duration and its error as collected by the
+	// workgroup, which is nil if it succeeded or its error was ignored.
+	TaskFinished(info TaskInfo, duration time.Duration, err error)
+	// RetryAttempted is called when a failed attempt of a goroutine is
+	// about to be retried, with the error of the failed attempt.
+	RetryAttempted(info TaskInfo, err error)
+}
+
+// NoopMetrics is a Metrics implementation that discards all
+// measurements.
+type NoopMetrics struct{}
+
+// TaskStarted implements Metrics.
+func (NoopMetrics) TaskStarted(TaskInfo) {}
+
+// TaskFinished implements Metrics.
+func (NoopMetrics) TaskFinished(TaskInfo, time.Duration, error) {}
+
+// RetryAttempted implements Metrics.
+func (NoopMetrics) RetryAttempted(TaskInfo, error) {}
+
+// WithMetrics sets the Metrics receiving the measurements of the
+// goroutines of the workgroup. By default, measurements are discarded.
+func WithMetrics(m Metrics) Option {
+	return func(g *Group) {
+		g.metrics = m
+	}
+}
+
+// instruments returns the Metrics receiving the measurements of the
+// workgroup: those set with WithMetrics and the logger set with
+// WithLogger.
+func (g *Group) instruments() Metrics {
+	var m multiMetrics
+	if g.metrics != nil {
+		m = append(m, g.metrics)
+	}
+	if g.logger != nil && g.logger.logger != nil {
+		m = append(m, g.logger)
+	}
+	switch len(m) {
+	case 0:
+		return NoopMetrics{}
+	case 1:
+		return m[0]
+	}
+	return m
+}
+
+// multiMetrics forwards measurements to several Metrics.
+type multiMetrics []Metrics
+
+func (m multiMetrics) TaskStarted(info TaskInfo) {
+	for _, mm := range m {
+		mm.TaskStarted(info)
+	}
+}
+
+func (m multiMetrics) TaskFinished(info TaskInfo, duration time.Duration, err error) {
+	for _, mm := range m {
+		mm.TaskFinished(info, duration, err)
+	}
+}
+
+func (m multiMetrics) RetryAttempted(info TaskInfo, err error) {
+	for _, mm := range m {
+		mm.RetryAttempted(info, err)
+	}
+}
@@ -0,0 +1,74 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a Metrics recording the measurements per task name.
+type recordingMetrics struct {
+	NoopMetrics
+
+	mu       sync.Mutex
+	started  map[string]int
+	retried  map[string]int
+	finished map[string]error
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		started:  make(map[string]int),
+		retried:  make(map[string]int),
+		finished: make(map[string]error),
+	}
+}
+
+func (m *recordingMetrics) TaskStarted(info TaskInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started[info.Name]++
+}
+
+func (m *recordingMetrics) TaskFinished(info TaskInfo, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished[info.Name] = err
+}
+
+func (m *recordingMetrics) RetryAttempted(info TaskInfo, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retried[info.Name]++
+}
+
+func TestGroup_WithMetrics(t *testing.T) {
+	m := newRecordingMetrics()
+	ctx, g := New(context.Background(), Collect,
+		WithMetrics(m),
+		WithRetry(Attempts(3), Delay(time.Millisecond)),
+	)
+
+	g.GoNamed(ctx, "ok", func() error {
+		return nil
+	})
+	g.GoNamed(ctx, "failing", func() error {
+		return errInternal
+	})
+	g.Wait()
+
+	if m.started["ok"] != 1 || m.started["failing"] != 1 {
+		t.Errorf("expected each goroutine to start once, but got %v", m.started)
+	}
+	if m.retried["ok"] != 0 || m.retried["failing"] != 2 {
+		t.Errorf("expected 2 retries of the failing goroutine, but got %v", m.retried)
+	}
+	if err := m.finished["ok"]; err != nil {
+		t.Errorf("expected the successful goroutine to finish without error, but got %v", err)
+	}
+	if err := m.finished["failing"]; !errors.Is(err, errInternal) {
+		t.Errorf("expected the failing goroutine to finish with %v, but got %v", errInternal, err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package otelworkgroup traces the goroutines of a workgroup with
+// OpenTelemetry. It is a separate module, so that the workgroup package
+// does not depend on OpenTelemetry.
+//
+//	ctx, g := workgroup.New(ctx, workgroup.Collect, otelworkgroup.Tracing())
+//	for _, user := range users {
+//		g.GoCtx(ctx, func(ctx context.Context) error {
+//			return syncUser(ctx, user) // traced as a child of the span of its goroutine
+//		}, workgroup.Name("sync-user"))
+//	}
+package otelworkgroup
+
+import (
+	"context"
+	"time"
+
+	"github.com/sadlil/workgroup"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scope is the name of the instrumentation scope of the spans.
+const scope = "github.com/sadlil/workgroup/otelworkgroup"
+
+// defaultSpanName is the name of the spans of unnamed goroutines.
+const defaultSpanName = "workgroup.task"
+
+// Option configures the tracing of a workgroup.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider creating the spans. It
+// defaults to the global provider, see otel.GetTracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// Tracing returns a workgroup option starting a span for every goroutine
+// of the workgroup, named after the goroutine, see workgroup.Name. The
+// span is a child of the context of the workgroup and is linked to the
+// span of the context the goroutine was submitted with, if different. It
+// records the retries of the goroutine as events, and its final error,
+// if any, with an error status.
+//
+// The span is carried by the context of the goroutine, as passed to the
+// function by GoCtx, so that the spans started by the function are its
+// children.
+func Tracing(opts ...Option) workgroup.Option {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return workgroup.WithHook(&hook{tracer: c.provider.Tracer(scope)})
+}
+
+// hook is the workgroup.Hook starting and ending the spans of the
+// goroutines, which are carried by their contexts.
+type hook struct {
+	tracer trace.Tracer
+}
+
+// Start implements workgroup.Hook.
+func (h *hook) Start(submit, ctx context.Context, info workgroup.TaskInfo) context.Context {
+	name := info.Name
+	if name == "" {
+		name = defaultSpanName
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String("workgroup.task.id", info.ID),
+			attribute.Int("workgroup.task.index", info.Index),
+		),
+	}
+	link := trace.LinkFromContext(submit)
+	if link.SpanContext.IsValid() && !link.SpanContext.Equal(trace.SpanContextFromContext(ctx)) {
+		opts = append(opts, trace.WithLinks(link))
+	}
+	ctx, _ = h.tracer.Start(ctx, name, opts...)
+	return ctx
+}
+
+// Retry implements workgroup.Hook.
+func (h *hook) Retry(ctx context.Context, info workgroup.TaskInfo, err error, delay time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attribute.Int("workgroup.task.attempt", info.Attempts),
+		attribute.String("workgroup.retry.delay", delay.String()),
+		attribute.String("exception.message", err.Error()),
+	))
+}
+
+// Finish implements workgroup.Hook.
+func (h *hook) Finish(ctx context.Context, info workgroup.TaskInfo, _ time.Duration, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("workgroup.task.attempts", info.Attempts))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
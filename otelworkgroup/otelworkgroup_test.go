@@ -0,0 +1,91 @@
+package otelworkgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sadlil/workgroup"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errInternal = errors.New("internal")
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	ctx, g := workgroup.New(ctx, workgroup.Collect,
+		workgroup.WithRetry(workgroup.Attempts(2), workgroup.Delay(time.Millisecond)),
+		Tracing(WithTracerProvider(tp)),
+	)
+
+	submitCtx, submitter := tracer.Start(ctx, "submitter")
+	var child trace.SpanContext
+	g.GoCtx(submitCtx, func(ctx context.Context) error {
+		_, span := tracer.Start(ctx, "child")
+		child = span.SpanContext()
+		span.End()
+		return nil
+	}, workgroup.Name("sync-user"))
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	submitter.End()
+	root.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	named := spans["sync-user"]
+	if named == nil {
+		t.Fatalf("expected a span named after the goroutine, but got %v", spans)
+	}
+	if named.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("expected the span to be a child of the context of the workgroup")
+	}
+	var id bool
+	for _, attr := range named.Attributes() {
+		id = id || attr.Key == "workgroup.task.id" && attr.Value.AsString() != ""
+	}
+	if !id {
+		t.Errorf("expected the span to carry the ID of the goroutine, but got %v", named.Attributes())
+	}
+	if links := named.Links(); len(links) != 1 || !links[0].SpanContext.Equal(submitter.SpanContext()) {
+		t.Errorf("expected the span to be linked to the submitting span, but got %v", links)
+	}
+	if spans["child"].Parent().SpanID() != named.SpanContext().SpanID() || child.TraceID() != root.SpanContext().TraceID() {
+		t.Errorf("expected the spans of the function to be children of the span of its goroutine")
+	}
+
+	failed := spans[defaultSpanName]
+	if failed == nil {
+		t.Fatalf("expected a span for the unnamed goroutine, but got %v", spans)
+	}
+	if failed.Status().Code != codes.Error {
+		t.Errorf("expected an error status, but got %v", failed.Status())
+	}
+	if len(failed.Links()) != 0 {
+		t.Errorf("expected no link when submitted with the context of the workgroup, but got %v", failed.Links())
+	}
+	var retries int
+	for _, event := range failed.Events() {
+		if event.Name == "retry" {
+			retries++
+		}
+	}
+	if retries != 1 {
+		t.Errorf("expected 1 retry event, but got %d", retries)
+	}
+}
@@ -0,0 +1,88 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+)
+
+// PanicPolicy defines how a workgroup handles goroutines that panic, see
+// WithPanicPolicy.
+type PanicPolicy int
+
+const (
+	// PanicRecover converts panics into errors, *PanicError, handled like
+	// any other error according to the failure mode. It is the default.
+	PanicRecover PanicPolicy = iota
+	// PanicRepanic lets the remaining goroutines complete, then panics in
+	// the goroutine calling Wait with the *PanicError of the first
+	// goroutine that panicked, which holds the original panic value and
+	// stack trace.
+	PanicRepanic
+	// PanicCancel converts panics into errors like PanicRecover, and
+	// cancels the workgroup immediately with the *PanicError as cause,
+	// whatever the failure mode.
+	PanicCancel
+)
+
+// WithPanicPolicy sets how the workgroup handles goroutines that panic,
+// which sets the blast radius of a crashing goroutine.
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(g *Group) {
+		g.panicPolicy = p
+	}
+}
+
+// WithPanicHandler registers fn, invoked with the value and the stack
+// trace of every panic recovered in a goroutine of the workgroup, before
+// the panic is converted into an error. It keeps crash reporting, e.g. to
+// Sentry, when panics are recovered. fn is called in the panicking
+// goroutine, also for the panics of the attempts of hedged goroutines and
+// of restarted services.
+func WithPanicHandler(fn func(v any, stack []byte, info TaskInfo)) Option {
+	return func(g *Group) {
+		g.panicHandler = fn
+	}
+}
+
+// recovered converts the value v recovered from a panic in t into a
+// *PanicError, after passing it to the panic handler. It must be called
+// by the deferred function recovering the panic, to capture its stack.
+func (g *Group) recovered(v any, t *task) *PanicError {
+	err := newPanicError(v)
+	if g.panicHandler != nil {
+		g.panicHandler(err.Value, err.Stack, t.info())
+	}
+	return err
+}
+
+// safeCall calls fn of t with ctx, recovering a panic as a *PanicError,
+// for functions that run in goroutines of their own.
+func (g *Group) safeCall(ctx context.Context, t *task, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = g.recovered(v, t)
+		}
+	}()
+	return fn(ctx)
+}
+
+// asPanicError returns the *PanicError in the chain of err, if any.
+func asPanicError(err error) *PanicError {
+	var panicErr *PanicError
+	errors.As(err, &panicErr)
+	return panicErr
+}
+
+// repanic panics with the first panic of a goroutine, if the panic policy
+// is PanicRepanic.
+func (g *Group) repanic() {
+	if g.panicPolicy != PanicRepanic {
+		return
+	}
+	g.errLock.Lock()
+	panicked := g.panicked
+	g.errLock.Unlock()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
@@ -0,0 +1,92 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithPanicPolicy_Repanic(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithPanicPolicy(PanicRepanic))
+
+	var completed bool
+	g.Go(ctx, func() error {
+		panic("boom")
+	})
+	g.Go(ctx, func() error {
+		completed = true
+		return nil
+	})
+
+	defer func() {
+		panicErr, ok := recover().(*PanicError)
+		if !ok || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+			t.Errorf("expected Wait to panic with the *PanicError of the goroutine, but got %v", panicErr)
+		}
+		if !completed {
+			t.Errorf("expected the other goroutines to complete")
+		}
+	}()
+	g.Wait()
+	t.Error("expected Wait to panic")
+}
+
+func TestGroup_WithPanicPolicy_Cancel(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithPanicPolicy(PanicCancel))
+
+	g.Go(ctx, func() error {
+		<-ctx.Done()
+		return nil
+	})
+	g.Go(ctx, func() error {
+		panic(errInternal)
+	})
+
+	var panicErr *PanicError
+	if err := g.Wait(); !errors.As(err, &panicErr) || !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want a *PanicError", err)
+	}
+	if cause := context.Cause(ctx); !errors.As(cause, &panicErr) {
+		t.Errorf("expected the workgroup to be canceled with the panic, but got %v", cause)
+	}
+}
+
+func TestGroup_WithPanicHandler(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		values []any
+	)
+	ctx, g := New(context.Background(), Collect, WithPanicHandler(func(v any, stack []byte, info TaskInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(stack) == 0 || info.ID == "" {
+			t.Errorf("expected the stack and the task of the panic, but got %q and %+v", stack, info)
+		}
+		values = append(values, v)
+	}))
+
+	g.Go(ctx, func() error {
+		panic("task")
+	})
+	g.GoHedged(ctx, time.Hour, func(context.Context) error {
+		panic("hedged")
+	})
+	g.Go(ctx, func() error {
+		return nil
+	})
+
+	var panicErr *PanicError
+	if err := g.Wait(); !errors.As(err, &panicErr) {
+		t.Errorf("group.Wait() = %v, want a *PanicError", err)
+	}
+	slices.SortFunc(values, func(a, b any) int {
+		return strings.Compare(a.(string), b.(string))
+	})
+	if want := []any{"hedged", "task"}; !slices.Equal(values, want) {
+		t.Errorf("expected the panic handler to be called with %v, but got %v", want, values)
+	}
+}
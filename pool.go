@@ -0,0 +1,94 @@
+package workgroup
+
+import "sync"
+
+// WithWorkerPool runs the goroutines of the workgroup on up to n
+// long-lived workers, fed by a queue, instead of spawning a new goroutine
+// per task. It avoids the cost of goroutine creation for large numbers of
+// tiny tasks. The workers are started as needed and stopped by Wait.
+//
+// Go does not block on the workers, which also limits the number of
+// concurrently executing goroutines to n; combine it with WithLimit to
+// bound the number of queued tasks.
+func WithWorkerPool(n int) Option {
+	return func(g *Group) {
+		g.pool = newPool(n)
+	}
+}
+
+// pool is a set of workers executing the functions of a queue.
+type pool struct {
+	size int
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []func()
+	workers int
+	stopped bool
+}
+
+func newPool(size int) *pool {
+	p := &pool{size: max(size, 1)}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// submit queues fn for execution by a worker, starting a new worker if
+// there are less than the size of the pool.
+func (p *pool) submit(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, fn)
+	p.stopped = false
+	if p.workers < p.size {
+		p.workers++
+		go p.work()
+		return
+	}
+	p.cond.Signal()
+}
+
+// stop makes the workers exit once the queue is empty. Functions
+// submitted afterwards start new workers.
+func (p *pool) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	p.cond.Broadcast()
+}
+
+func (p *pool) work() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		for len(p.queue) == 0 && !p.stopped {
+			p.cond.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.workers--
+			return
+		}
+		fn := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+
+		p.mu.Unlock()
+		p.run(fn)
+		p.mu.Lock()
+	}
+}
+
+// run calls fn, starting a new worker in place of the current one if fn
+// calls runtime.Goexit, with the lock held for the deferred unlock of
+// work.
+func (p *pool) run(fn func()) {
+	exited := true
+	defer func() {
+		if exited {
+			p.mu.Lock()
+			go p.work()
+		}
+	}()
+	fn()
+	exited = false
+}
@@ -0,0 +1,72 @@
+package workgroup
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithWorkerPool(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithWorkerPool(4))
+
+	var (
+		count   atomic.Int32
+		current atomic.Int32
+		maxSeen atomic.Int32
+	)
+	for i := 0; i < 1000; i++ {
+		g.Go(ctx, func() error {
+			c := current.Add(1)
+			for {
+				m := maxSeen.Load()
+				if c <= m || maxSeen.CompareAndSwap(m, c) {
+					break
+				}
+			}
+			count.Add(1)
+			current.Add(-1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	if c := count.Load(); c != 1000 {
+		t.Errorf("expected 1000 goroutines to run, but got %d", c)
+	}
+	if m := maxSeen.Load(); m > 4 {
+		t.Errorf("expected at most 4 concurrent goroutines, but got %d", m)
+	}
+	waitFor(t, func() bool {
+		g.pool.mu.Lock()
+		defer g.pool.mu.Unlock()
+		return g.pool.workers == 0
+	})
+}
+
+func TestGroup_WithWorkerPool_Keyed(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2), WithWorkerPool(1))
+
+	var count atomic.Int32
+	for i := 0; i < 20; i++ {
+		g.GoKeyed(ctx, "key", func() error {
+			count.Add(1)
+			runtime.Gosched()
+			return nil
+		})
+		g.Go(ctx, func() error {
+			time.Sleep(time.Microsecond)
+			count.Add(1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if c := count.Load(); c != 40 {
+		t.Errorf("expected 40 goroutines to run, but got %d", c)
+	}
+}
@@ -0,0 +1,48 @@
+package workgroup
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+// WithName names the workgroup, which identifies it in the profiling
+// labels set with WithProfilingLabels and in debugging tools, see the
+// httpdebug package.
+func WithName(name string) Option {
+	return func(g *Group) {
+		g.name = name
+	}
+}
+
+// Name returns the name of the workgroup set with WithName.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// WithProfilingLabels runs every goroutine of the workgroup with pprof
+// labels identifying it: "workgroup", the name set with WithName if any,
+// and "task", the name of the task or its index. CPU and goroutine
+// profiles of large fan-outs then tell the tasks apart, e.g. with
+// go tool pprof -tagfocus. Goroutines started by the tasks inherit the
+// labels.
+func WithProfilingLabels() Option {
+	return func(g *Group) {
+		g.profilingLabels = true
+	}
+}
+
+// labeled calls fn with the profiling labels of t.
+func (g *Group) labeled(t *task, fn func()) {
+	name := t.name
+	if name == "" {
+		name = strconv.Itoa(t.index)
+	}
+	labels := []string{"task", name}
+	if g.name != "" {
+		labels = append(labels, "workgroup", g.name)
+	}
+	pprof.Do(context.Background(), pprof.Labels(labels...), func(context.Context) {
+		fn()
+	})
+}
@@ -0,0 +1,24 @@
+package workgroup
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestGroup_WithProfilingLabels(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithName("batch"), WithProfilingLabels())
+
+	var profile bytes.Buffer
+	g.GoNamed(ctx, "fetch", func() error {
+		return pprof.Lookup("goroutine").WriteTo(&profile, 1)
+	})
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `labels: {"task":"fetch", "workgroup":"batch"}`; !bytes.Contains(profile.Bytes(), []byte(want)) {
+		t.Errorf("expected the goroutine profile to contain %s, but got:\n%s", want, profile.String())
+	}
+}
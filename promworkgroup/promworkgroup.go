@@ -0,0 +1,199 @@
+// Package promworkgroup exposes the metrics of workgroups to Prometheus.
+// It is a separate module, so that the workgroup package does not depend
+// on the Prometheus client.
+//
+//	c := promworkgroup.NewCollector()
+//	prometheus.MustRegister(c)
+//
+//	ctx, g := workgroup.New(ctx, workgroup.Collect, c.Instrument("ingest"))
+package promworkgroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sadlil/workgroup"
+)
+
+// label is the label holding the name of the workgroup, see
+// Collector.Instrument.
+const label = "workgroup"
+
+// Option configures a Collector.
+type Option func(*config)
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace sets the prefix of the names of the metrics. It defaults
+// to "workgroup".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets, in seconds, of the histogram of the
+// durations of the goroutines. It defaults to prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// Collector is a prometheus.Collector exposing the metrics of the
+// workgroups instrumented with it, labeled with their name:
+//   - tasks_running and tasks_pending, the gauges of the goroutines
+//     currently executing and waiting to, see workgroup.Stats,
+//   - task_duration_seconds, the histogram of the durations of the
+//     goroutines, including their retries,
+//   - task_retries_total and task_failures_total, the counters of the
+//     retries and of the failed goroutines.
+//
+// The gauges of workgroups with the same name are summed up. A workgroup
+// is no longer reported once it has finished, and is reported again from
+// the start of its first goroutine after Reset.
+type Collector struct {
+	running  *prometheus.Desc
+	pending  *prometheus.Desc
+	duration *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+	failures *prometheus.CounterVec
+
+	mu     sync.Mutex
+	groups map[*workgroup.Group]string
+}
+
+// NewCollector creates a new Collector with the specified options.
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{namespace: "workgroup", buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Collector{
+		running: prometheus.NewDesc(prometheus.BuildFQName(cfg.namespace, "", "tasks_running"),
+			"Number of goroutines of the workgroup currently executing.", []string{label}, nil),
+		pending: prometheus.NewDesc(prometheus.BuildFQName(cfg.namespace, "", "tasks_pending"),
+			"Number of goroutines of the workgroup accepted but not started yet.", []string{label}, nil),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "task_duration_seconds",
+			Help:      "Duration of the goroutines of the workgroup, including their retries.",
+			Buckets:   cfg.buckets,
+		}, []string{label}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "task_retries_total",
+			Help:      "Number of retries of the goroutines of the workgroup.",
+		}, []string{label}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "task_failures_total",
+			Help:      "Number of failed goroutines of the workgroup.",
+		}, []string{label}),
+		groups: make(map[*workgroup.Group]string),
+	}
+}
+
+// Instrument returns a workgroup option reporting the metrics of the
+// workgroup to c, labeled with name, until it has finished.
+func (c *Collector) Instrument(name string) workgroup.Option {
+	return func(g *workgroup.Group) {
+		h := &hook{c: c, g: g, name: name}
+		workgroup.WithHook(h)(g)
+		h.register()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.running
+	ch <- c.pending
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+	c.failures.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	groups := make(map[*workgroup.Group]string, len(c.groups))
+	for g, name := range c.groups {
+		groups[g] = name
+	}
+	c.mu.Unlock()
+
+	running := make(map[string]int)
+	pending := make(map[string]int)
+	for g, name := range groups {
+		stats := g.Stats()
+		running[name] += stats.Running
+		pending[name] += stats.Pending
+	}
+	for name, n := range running {
+		ch <- prometheus.MustNewConstMetric(c.running, prometheus.GaugeValue, float64(n), name)
+	}
+	for name, n := range pending {
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(n), name)
+	}
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+	c.failures.Collect(ch)
+}
+
+// hook is the workgroup.Hook of a workgroup instrumented by c.
+type hook struct {
+	workgroup.NoopHook
+	c    *Collector
+	g    *workgroup.Group
+	name string
+	// registered is whether g is reported by c.
+	registered atomic.Bool
+}
+
+// register reports g until it has finished, unless it is reported
+// already.
+func (h *hook) register() {
+	h.c.mu.Lock()
+	if h.registered.Load() {
+		h.c.mu.Unlock()
+		return
+	}
+	h.registered.Store(true)
+	h.c.groups[h.g] = h.name
+	h.c.mu.Unlock()
+
+	h.g.Defer(func() {
+		h.c.mu.Lock()
+		defer h.c.mu.Unlock()
+		delete(h.c.groups, h.g)
+		h.registered.Store(false)
+	})
+}
+
+// Start implements workgroup.Hook.
+func (h *hook) Start(_, ctx context.Context, _ workgroup.TaskInfo) context.Context {
+	if !h.registered.Load() {
+		// The workgroup was reset after it had finished.
+		h.register()
+	}
+	return ctx
+}
+
+// Retry implements workgroup.Hook.
+func (h *hook) Retry(context.Context, workgroup.TaskInfo, error, time.Duration) {
+	h.c.retries.WithLabelValues(h.name).Inc()
+}
+
+// Finish implements workgroup.Hook.
+func (h *hook) Finish(_ context.Context, _ workgroup.TaskInfo, duration time.Duration, err error) {
+	h.c.duration.WithLabelValues(h.name).Observe(duration.Seconds())
+	if err != nil {
+		h.c.failures.WithLabelValues(h.name).Inc()
+	}
+}
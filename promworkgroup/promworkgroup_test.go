@@ -0,0 +1,96 @@
+package promworkgroup
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sadlil/workgroup"
+)
+
+var errInternal = errors.New("internal")
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+
+	ctx, g := workgroup.New(context.Background(), workgroup.Collect,
+		workgroup.WithLimit(1),
+		workgroup.WithRetry(workgroup.Attempts(3), workgroup.Delay(time.Millisecond)),
+		c.Instrument("ingest"),
+	)
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+	g.Submit(ctx, func() error {
+		return errInternal
+	})
+	for g.Stats().Pending != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	const gauges = `
+# HELP workgroup_tasks_pending Number of goroutines of the workgroup accepted but not started yet.
+# TYPE workgroup_tasks_pending gauge
+workgroup_tasks_pending{workgroup="ingest"} 1
+# HELP workgroup_tasks_running Number of goroutines of the workgroup currently executing.
+# TYPE workgroup_tasks_running gauge
+workgroup_tasks_running{workgroup="ingest"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(gauges), "workgroup_tasks_running", "workgroup_tasks_pending"); err != nil {
+		t.Error(err)
+	}
+
+	close(release)
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+
+	if n := testutil.ToFloat64(c.retries.WithLabelValues("ingest")); n != 2 {
+		t.Errorf("expected 2 retries, but got %v", n)
+	}
+	if n := testutil.ToFloat64(c.failures.WithLabelValues("ingest")); n != 1 {
+		t.Errorf("expected 1 failure, but got %v", n)
+	}
+	if n := testutil.CollectAndCount(c.duration); n != 1 {
+		t.Errorf("expected the histogram of the durations of the workgroup, but got %d", n)
+	}
+	if n := testutil.CollectAndCount(c, "workgroup_tasks_running"); n != 0 {
+		t.Errorf("expected the finished workgroup not to be reported, but got %d gauges", n)
+	}
+}
+
+func TestCollector_Reset(t *testing.T) {
+	c := NewCollector()
+	ctx, g := workgroup.New(context.Background(), workgroup.Collect, c.Instrument("ingest"))
+	g.Go(ctx, func() error {
+		return nil
+	})
+	g.Wait()
+
+	ctx = g.Reset()
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+	for g.Stats().Running != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if n := testutil.CollectAndCount(c, "workgroup_tasks_running"); n != 1 {
+		t.Errorf("expected the reset workgroup to be reported, but got %d gauges", n)
+	}
+	close(release)
+	g.Wait()
+	if n := testutil.CollectAndCount(c, "workgroup_tasks_running"); n != 0 {
+		t.Errorf("expected the finished workgroup not to be reported, but got %d gauges", n)
+	}
+}
@@ -0,0 +1,81 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrQueueFull is returned by Submit when the queue of the workgroup is
+// full and its policy is QueueReject.
+var ErrQueueFull = errors.New("workgroup: queue is full")
+
+// QueuePolicy defines how Submit behaves when the queue of the workgroup
+// is full.
+type QueuePolicy int
+
+const (
+	// QueueBlock makes Submit wait until there is room in the queue.
+	QueueBlock QueuePolicy = iota
+	// QueueDrop makes Submit silently discard the function.
+	QueueDrop
+	// QueueReject makes Submit return ErrQueueFull.
+	QueueReject
+)
+
+// WithQueue bounds the number of functions submitted with Submit that are
+// waiting for the concurrency limit to size. The policy defines what
+// happens when the queue is full. Without WithQueue, the queue is
+// unbounded.
+func WithQueue(size int, policy QueuePolicy) Option {
+	return func(g *Group) {
+		g.queue = newLimiter(size)
+		g.queuePolicy = policy
+	}
+}
+
+// Submit queues fn for execution in a new goroutine of the workgroup,
+// like Go, but without waiting for the concurrency limit: fn is launched
+// once the limit allows it. This decouples the producers of work from
+// its execution. If the queue set with WithQueue is full, Submit blocks,
+// drops fn or returns ErrQueueFull depending on the queue policy. While
+// blocked, it returns the cause of ctx once ctx is done.
+//
+// Wait also waits for the queued functions to be launched and to finish.
+func (g *Group) Submit(ctx context.Context, fn func() error, opts ...TaskOption) error {
+	if err := g.accepting(); err != nil {
+		return err
+	}
+	t := g.newTask(fn, opts)
+	t.submit = ctx
+	g.emitSubmit(t)
+	if q := g.queue; q != nil {
+		switch g.queuePolicy {
+		case QueueDrop:
+			if !q.tryAcquire(1) {
+				return nil
+			}
+		case QueueReject:
+			if !q.tryAcquire(1) {
+				return ErrQueueFull
+			}
+		default:
+			if err := q.acquire(ctx, t.waiter(1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.pending.Add(1)
+	g.enter()
+	go func() {
+		defer g.leave()
+		release := g.add(t)
+		if g.queue != nil {
+			g.queue.release(1)
+		}
+		// run counts t as pending until it executes.
+		g.pending.Add(-1)
+		g.run(t, release)
+	}()
+	return nil
+}
@@ -0,0 +1,57 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    QueuePolicy
+		wantErr   error
+		wantCount int32
+	}{
+		{name: "drop", policy: QueueDrop, wantErr: nil, wantCount: 3},
+		{name: "reject", policy: QueueReject, wantErr: ErrQueueFull, wantCount: 3},
+		{name: "block", policy: QueueBlock, wantErr: context.DeadlineExceeded, wantCount: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, g := New(context.Background(), Collect, WithLimit(1), WithQueue(2, tc.policy))
+
+			release := make(chan struct{})
+			var started, count int32
+			fn := func() error {
+				atomic.AddInt32(&started, 1)
+				<-release
+				atomic.AddInt32(&count, 1)
+				return nil
+			}
+
+			// One running and two queued functions.
+			for i := 0; i < 3; i++ {
+				if err := g.Submit(ctx, fn); err != nil {
+					t.Fatalf("Submit() = %v, want nil", err)
+				}
+				waitFor(t, func() bool { return atomic.LoadInt32(&started) == 1 })
+			}
+
+			submitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			if err := g.Submit(submitCtx, fn); !errors.Is(err, tc.wantErr) {
+				t.Errorf("Submit() on a full queue = %v, want %v", err, tc.wantErr)
+			}
+
+			close(release)
+			g.Wait()
+			if count != tc.wantCount {
+				t.Errorf("expected %d goroutines to run, but got %d", tc.wantCount, count)
+			}
+		})
+	}
+}
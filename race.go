@@ -0,0 +1,61 @@
+package workgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// Race is a typed workgroup that runs its tasks in FirstSuccess mode and
+// returns the value of the first task to succeed.
+//
+// It is useful for querying replicas or mirrors where only one answer is
+// required: as soon as a task succeeds, the context of the remaining
+// tasks is canceled.
+type Race[T any] struct {
+	g *Group
+
+	once  sync.Once
+	value T
+}
+
+// NewRace creates a new Race with the specified options. It returns a
+// context that is derived from `ctx` and is canceled as soon as a task
+// succeeds, when the race finishes, or when it is canceled explicitly.
+func NewRace[T any](ctx context.Context, opts ...Option) (context.Context, *Race[T]) {
+	ctx, g := New(ctx, FirstSuccess, opts...)
+	return ctx, &Race[T]{g: g}
+}
+
+// Go launches a new goroutine competing in the race. It follows the same
+// retry policy and concurrency limit as Group.Go, and accepts the same
+// task options.
+func (r *Race[T]) Go(ctx context.Context, fn func() (T, error), opts ...TaskOption) {
+	r.g.Go(ctx, func() error {
+		v, err := fn()
+		if err != nil {
+			return err
+		}
+		r.once.Do(func() {
+			r.value = v
+		})
+		return nil
+	}, opts...)
+}
+
+// Wait blocks until all goroutines in the race have completed. It returns
+// the value of the winning goroutine, or the zero value of T and an error
+// matching ErrNoSuccess, joined with all failures, if no goroutine
+// succeeded.
+func (r *Race[T]) Wait() (T, error) {
+	if err := r.g.Wait(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return r.value, nil
+}
+
+// Cancel cancels the race context, signaling all running goroutines to
+// stop.
+func (r *Race[T]) Cancel() {
+	r.g.Cancel()
+}
@@ -0,0 +1,68 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRace(t *testing.T) {
+	ctx, r := NewRace[string](context.Background())
+
+	r.Go(ctx, func() (string, error) {
+		return "", fmt.Errorf("replica-1: %w", errInternal)
+	})
+	r.Go(ctx, func() (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "replica-2", nil
+	})
+	r.Go(ctx, func() (string, error) {
+		select {
+		case <-time.After(time.Second):
+			return "replica-3", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+
+	got, err := r.Wait()
+	if err != nil {
+		t.Fatalf("race.Wait() = %v, want nil", err)
+	}
+	if got != "replica-2" {
+		t.Errorf("race.Wait() = %q, want %q", got, "replica-2")
+	}
+}
+
+func TestRace_AllFailed(t *testing.T) {
+	ctx, r := NewRace[int](context.Background())
+	r.Go(ctx, func() (int, error) { return 1, errInternal })
+	r.Go(ctx, func() (int, error) { return 2, errInvalid })
+
+	got, err := r.Wait()
+	if !errors.Is(err, errInternal) || !errors.Is(err, errInvalid) || !errors.Is(err, ErrNoSuccess) {
+		t.Errorf("race.Wait() error = %v, want %v, %v and %v", err, errInternal, errInvalid, ErrNoSuccess)
+	}
+	if got != 0 {
+		t.Errorf("race.Wait() = %d, want 0", got)
+	}
+}
+
+func TestRace_NoTasks(t *testing.T) {
+	_, r := NewRace[int](context.Background())
+	if _, err := r.Wait(); !errors.Is(err, ErrNoSuccess) {
+		t.Errorf("race.Wait() error = %v, want %v", err, ErrNoSuccess)
+	}
+}
+
+func TestRace_TaskOptions(t *testing.T) {
+	ctx, r := NewRace[int](context.Background())
+	r.Go(ctx, func() (int, error) { return 0, errInternal }, Name("replica-1"))
+
+	var taskErr *TaskError
+	if _, err := r.Wait(); !errors.As(err, &taskErr) || taskErr.Name != "replica-1" {
+		t.Errorf("race.Wait() error = %v, want the error of the task named replica-1", err)
+	}
+}
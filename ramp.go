@@ -0,0 +1,88 @@
+package workgroup
+
+import "time"
+
+// WithRampUp makes the concurrency limit of the workgroup grow gradually,
+// starting at start goroutines when the first goroutine is admitted and
+// increasing by step every interval, until it reaches the limit set with
+// WithLimit, if any. It avoids overwhelming a cold downstream system with
+// full parallelism at once.
+//
+// A non-positive interval disables the ramp-up.
+func WithRampUp(start, step int, interval time.Duration) Option {
+	return func(g *Group) {
+		if interval <= 0 {
+			g.rampUp = nil
+			return
+		}
+		g.rampUp = &rampUp{
+			start:    max(start, 1),
+			step:     max(step, 1),
+			interval: interval,
+		}
+	}
+}
+
+// rampUp is the state of the ramp-up of a limiter.
+type rampUp struct {
+	start    int
+	step     int
+	interval time.Duration
+	clock    Clock
+
+	begin   time.Time
+	ticking bool
+}
+
+// ceiling returns the current limit of the ramp-up, given the limit of
+// the limiter. It reports false once the ramp-up has reached the limit.
+func (r *rampUp) ceiling(limit int) (int, bool) {
+	now := r.clock.Now()
+	if r.begin.IsZero() {
+		r.begin = now
+	}
+	n := r.start
+	if r.interval > 0 {
+		n += r.step * int(now.Sub(r.begin)/r.interval)
+	}
+	if limit >= 0 && n >= limit {
+		return limit, false
+	}
+	return n, true
+}
+
+// rampLimit applies the ramp-up of l, if any, to limit. While the ramp-up
+// holds back waiters, it admits them as the limit grows. l.mu must be
+// held.
+func (l *limiter) rampLimit(limit int) int {
+	if l.ramp == nil {
+		return limit
+	}
+	n, ramping := l.ramp.ceiling(limit)
+	if !ramping {
+		l.ramp = nil
+		return limit
+	}
+	if !l.ramp.ticking {
+		l.ramp.ticking = true
+		go l.tick(l.ramp)
+	}
+	return n
+}
+
+// tick admits the waiters at every step of the ramp-up r.
+func (l *limiter) tick(r *rampUp) {
+	for {
+		timer := r.clock.NewTimer(r.interval)
+		<-timer.C()
+
+		l.mu.Lock()
+		l.admit()
+		if l.ramp != r || len(l.waiters) == 0 {
+			r.ticking = false
+			l.mu.Unlock()
+			return
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,68 @@
+package workgroup
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithRampUp(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(5), WithRampUp(1, 2, 50*time.Millisecond))
+
+	var (
+		mu      sync.Mutex
+		current int
+		peaks   []int
+	)
+	start := time.Now()
+	for i := 0; i < 40; i++ {
+		g.Submit(ctx, func() error {
+			mu.Lock()
+			current++
+			step := int(time.Since(start) / (50 * time.Millisecond))
+			for len(peaks) <= step {
+				peaks = append(peaks, 0)
+			}
+			peaks[step] = max(peaks[step], current)
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			current--
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+
+	if peaks[0] != 1 {
+		t.Errorf("expected 1 concurrent goroutine at first, but got %d", peaks[0])
+	}
+	for step, peak := range peaks {
+		if want := min(1+2*step, 5); peak > want {
+			t.Errorf("expected at most %d concurrent goroutines at step %d, but got %d", want, step, peak)
+		}
+	}
+}
+
+func TestGroup_WithRampUp_NoInterval(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(3), WithRampUp(1, 1, 0))
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		g.Submit(ctx, func() error {
+			// Blocks unless the 3 goroutines run concurrently.
+			wg.Done()
+			wg.Wait()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+}
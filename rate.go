@@ -0,0 +1,57 @@
+package workgroup
+
+// WithFailureRate cancels the workgroup once the ratio of failed
+// goroutines among the last `window` completed goroutines exceeds rate,
+// where rate is a fraction between 0 and 1 (e.g. 0.25 for 25%).
+//
+// The rate is only evaluated once at least `window` goroutines have
+// completed, so that a couple of early failures do not cancel the whole
+// workgroup. All errors are still collected and returned from Wait().
+func WithFailureRate(rate float64, window int) Option {
+	if window < 1 {
+		window = 1
+	}
+	return func(g *Group) {
+		g.failureRate = &failureRate{
+			limit:    rate,
+			outcomes: make([]bool, window),
+		}
+	}
+}
+
+// failureRate tracks the outcomes of the most recently completed
+// goroutines in a ring buffer.
+type failureRate struct {
+	limit float64
+
+	outcomes []bool
+	next     int
+	seen     int
+	failed   int
+}
+
+// observe records the outcome of a completed goroutine and reports
+// whether the failure rate over the window exceeds the limit.
+func (r *failureRate) observe(failed bool) bool {
+	if r.seen == len(r.outcomes) {
+		// Evict the oldest outcome from the window.
+		if r.outcomes[r.next] {
+			r.failed--
+		}
+	} else {
+		r.seen++
+	}
+	r.outcomes[r.next] = failed
+	if failed {
+		r.failed++
+	}
+	r.next = (r.next + 1) % len(r.outcomes)
+
+	return r.seen == len(r.outcomes) && float64(r.failed)/float64(r.seen) > r.limit
+}
+
+// reset forgets all observed outcomes.
+func (r *failureRate) reset() {
+	clear(r.outcomes)
+	r.next, r.seen, r.failed = 0, 0, 0
+}
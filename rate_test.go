@@ -0,0 +1,60 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFailureRate_Observe(t *testing.T) {
+	r := &failureRate{limit: 0.5, outcomes: make([]bool, 4)}
+
+	steps := []struct {
+		failed bool
+		want   bool
+	}{
+		{failed: true, want: false},  // window not full yet
+		{failed: true, want: false},  // window not full yet
+		{failed: true, want: false},  // window not full yet
+		{failed: false, want: true},  // 3/4 failed
+		{failed: false, want: false}, // 2/4 failed
+		{failed: false, want: false}, // 1/4 failed
+		{failed: true, want: false},  // 1/4 failed
+		{failed: true, want: false},  // 2/4 failed
+		{failed: true, want: true},   // 3/4 failed
+	}
+	for i, step := range steps {
+		if got := r.observe(step.failed); got != step.want {
+			t.Fatalf("step %d: observe(%v) = %v, want %v", i, step.failed, got, step.want)
+		}
+	}
+}
+
+func TestGroup_WithFailureRate(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1), WithFailureRate(0.25, 4))
+
+	var ran int
+	for i := 0; i < 20; i++ {
+		g.Go(ctx, func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			ran++
+			if i%2 == 0 {
+				return errInternal
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(err, context.Canceled) = false, want true")
+	}
+	if ran != 4 {
+		t.Errorf("expected 4 goroutines to run before cancellation, but got %d", ran)
+	}
+}
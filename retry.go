@@ -0,0 +1,339 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryOption configures the retry policy of a workgroup, see WithRetry,
+// or of a single task, see TaskRetry.
+type RetryOption func(*retryPolicy)
+
+// DelayFunc computes the delay before the next attempt of a task, given
+// the number of attempts made so far and the base delay set with Delay.
+type DelayFunc func(attempt int, delay time.Duration) time.Duration
+
+// Default values of the retry policy. A task is only retried if the
+// number of attempts is raised with Attempts.
+const (
+	DefaultAttempts  = 1
+	DefaultDelay     = 100 * time.Millisecond
+	DefaultMaxJitter = 100 * time.Millisecond
+)
+
+// Attempts sets the maximum number of times a task is executed, including
+// the first attempt. Values below 1 are treated as 1.
+func Attempts(n int) RetryOption {
+	return func(p *retryPolicy) {
+		p.attempts = n
+	}
+}
+
+// Delay sets the base delay between attempts. It defaults to
+// DefaultDelay.
+func Delay(d time.Duration) RetryOption {
+	return func(p *retryPolicy) {
+		p.delay = d
+	}
+}
+
+// MaxDelay caps the delay between attempts, jitter included. By default
+// the delay is not capped.
+func MaxDelay(d time.Duration) RetryOption {
+	return func(p *retryPolicy) {
+		p.maxDelay = d
+	}
+}
+
+// MaxJitter sets the maximum random duration added to the delay between
+// attempts, which spreads out the retries of concurrent tasks. It
+// defaults to DefaultMaxJitter, zero disables jitter.
+func MaxJitter(d time.Duration) RetryOption {
+	return func(p *retryPolicy) {
+		p.maxJitter = d
+	}
+}
+
+// DelayType sets how the delay between attempts grows. It defaults to
+// BackOffDelay.
+func DelayType(fn DelayFunc) RetryOption {
+	return func(p *retryPolicy) {
+		p.delayType = fn
+	}
+}
+
+// RetryIf sets a predicate deciding whether a failed attempt is retried.
+// By default, all errors are retried except permanent ones, see
+// Permanent. Permanent errors are never retried, regardless of the
+// predicate.
+func RetryIf(fn func(err error) bool) RetryOption {
+	return func(p *retryPolicy) {
+		p.retryIf = fn
+	}
+}
+
+// Retrier replaces the built-in retry engine with do, which must execute
+// fn, retrying it as it sees fit, until it succeeds or ctx is done. All
+// other retry options are ignored, as are WithRetryBudget,
+// WithRetryRateLimit and WithOnRetry, and retries are not counted in the
+// Retried stat. It is the extension point used to plug in third-party
+// retry libraries, see the retrygo module.
+func Retrier(do func(ctx context.Context, fn func() error) error) RetryOption {
+	return func(p *retryPolicy) {
+		p.retrier = do
+	}
+}
+
+// BackOffDelay is a DelayFunc that doubles the delay after every attempt.
+func BackOffDelay(attempt int, delay time.Duration) time.Duration {
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+// FixedDelay is a DelayFunc that keeps the delay constant.
+func FixedDelay(_ int, delay time.Duration) time.Duration {
+	return delay
+}
+
+// retryPolicy is the retry configuration of a task.
+type retryPolicy struct {
+	attempts  int
+	delay     time.Duration
+	maxDelay  time.Duration
+	maxJitter time.Duration
+	delayType DelayFunc
+	retryIf   func(err error) bool
+	retrier   func(ctx context.Context, fn func() error) error
+	// budget, if set, limits the retries shared by all tasks of the
+	// workgroup.
+	budget *retryBudget
+	// limiter, if set, limits the rate of the retries of all tasks of
+	// the workgroup.
+	limiter *retryLimiter
+	clock   Clock
+	// onRetry, if set, is called before waiting for the next attempt.
+	onRetry func(err error, delay time.Duration)
+}
+
+// newRetryPolicy creates a retry policy from the default values and the
+// given options, applied in order.
+func newRetryPolicy(opts ...[]RetryOption) *retryPolicy {
+	p := &retryPolicy{
+		attempts:  DefaultAttempts,
+		delay:     DefaultDelay,
+		maxJitter: DefaultMaxJitter,
+		delayType: BackOffDelay,
+		clock:     systemClock{},
+	}
+	for _, o := range opts {
+		for _, opt := range o {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// do executes fn until it succeeds, fails permanently, runs out of
+// attempts, or ctx is done. The delay requested by a failed attempt, see
+// retryAfter, takes precedence over the backoff of the policy. A retry is
+// skipped if its delay ends after the deadline of ctx. It returns the
+// error of the last attempt, or the error of ctx if ctx is done before
+// the first attempt.
+func (p *retryPolicy) do(ctx context.Context, fn func() error) error {
+	if p.retrier != nil {
+		return p.retrier(ctx, fn)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !p.retryable(attempt, err) {
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if d, ok := retryAfter(err); ok {
+			delay = d
+		}
+		// Do not sleep past the deadline only to fail with
+		// context.DeadlineExceeded, which would hide the actual failure.
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(p.clock.Now()) < delay {
+			return err
+		}
+		if p.budget != nil && !p.budget.take() {
+			return err
+		}
+		if p.onRetry != nil {
+			p.onRetry(err, delay)
+		}
+		timer := p.clock.NewTimer(delay)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			// Report the actual failure rather than the cancellation.
+			timer.Stop()
+			return err
+		}
+		if p.limiter != nil && !p.limiter.wait(ctx, p.clock) {
+			return err
+		}
+	}
+}
+
+// retryable reports whether another attempt should follow the given
+// failed attempt.
+func (p *retryPolicy) retryable(attempt int, err error) bool {
+	if attempt >= p.attempts || IsPermanent(err) {
+		return false
+	}
+	return p.retryIf == nil || p.retryIf(err)
+}
+
+// backoff returns the delay before the attempt following the given one.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.delayType(attempt, p.delay)
+	if p.maxJitter > 0 {
+		d += rand.N(p.maxJitter)
+	}
+	if p.maxDelay > 0 && d > p.maxDelay {
+		d = p.maxDelay
+	}
+	return d
+}
+
+// retryAfter returns the delay requested by an error in err's chain
+// implementing
+//
+//	interface{ RetryAfter() time.Duration }
+//
+// such as an error built from a rate-limited HTTP response.
+func retryAfter(err error) (time.Duration, bool) {
+	var r interface{ RetryAfter() time.Duration }
+	if !errors.As(err, &r) {
+		return 0, false
+	}
+	return max(r.RetryAfter(), 0), true
+}
+
+// retryBudget is the number of retries left to the tasks of a workgroup.
+type retryBudget struct {
+	size      int
+	remaining atomic.Int64
+}
+
+func newRetryBudget(n int) *retryBudget {
+	b := &retryBudget{size: n}
+	b.reset()
+	return b
+}
+
+// reset refills the budget.
+func (b *retryBudget) reset() {
+	b.remaining.Store(int64(b.size))
+}
+
+// take consumes a retry from the budget and reports whether one was
+// left.
+func (b *retryBudget) take() bool {
+	return b.remaining.Add(-1) >= 0
+}
+
+// retryLimiter spaces out the retries of the tasks of a workgroup so that
+// they do not exceed a given rate.
+type retryLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRetryLimiter(n int, per time.Duration) *retryLimiter {
+	return &retryLimiter{interval: per / time.Duration(max(n, 1))}
+}
+
+// wait blocks until the next retry is allowed. It returns false if ctx is
+// done first.
+func (l *retryLimiter) wait(ctx context.Context, clock Clock) bool {
+	l.mu.Lock()
+	now := clock.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return true
+	}
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Permanent wraps err to signal that a task failed permanently and must
+// not be retried, even if the workgroup has a retry policy. The returned
+// error matches err with errors.Is and errors.As. Permanent returns nil if
+// err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether any error in err's chain is marked as
+// permanent, either by Permanent or by implementing
+//
+//	interface{ Permanent() bool }
+//
+// and returning true.
+func IsPermanent(err error) bool {
+	var p interface{ Permanent() bool }
+	return errors.As(err, &p) && p.Permanent()
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func (e *permanentError) Permanent() bool {
+	return true
+}
+
+// retryable adapts the function of t to the retry policy of the
+// workgroup: it counts the attempts of the task and recovers a panic in
+// the task as a *PanicError, which is never retried.
+func (g *Group) retryable(ctx context.Context, t *task) func() error {
+	return func() (err error) {
+		t.attempts.Add(1)
+		defer func() {
+			if v := recover(); v != nil {
+				err = Permanent(g.recovered(v, t))
+			}
+		}()
+		return t.fn(ctx)
+	}
+}
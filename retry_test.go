@@ -0,0 +1,330 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type temporaryError struct {
+	permanent bool
+}
+
+func (e temporaryError) Error() string   { return "temporary" }
+func (e temporaryError) Permanent() bool { return e.permanent }
+
+type retryAfterError struct {
+	after time.Duration
+}
+
+func (e retryAfterError) Error() string {
+	return fmt.Sprintf("retry after %v", e.after)
+}
+
+func (e retryAfterError) RetryAfter() time.Duration {
+	return e.after
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain", err: errInternal, want: false},
+		{name: "permanent", err: Permanent(errInternal), want: true},
+		{name: "wrapped_permanent", err: fmt.Errorf("wrapped: %w", Permanent(errInternal)), want: true},
+		{name: "interface_true", err: temporaryError{permanent: true}, want: true},
+		{name: "interface_false", err: temporaryError{permanent: false}, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsPermanent(tc.err); got != tc.want {
+				t.Errorf("IsPermanent(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGroup_PermanentError(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithRetry(Attempts(5), Delay(time.Millisecond)))
+
+	var count int32
+	g.Go(ctx, func() error {
+		atomic.AddInt32(&count, 1)
+		return Permanent(fmt.Errorf("bad request: %w", errInvalid))
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInvalid)
+	}
+	if !IsPermanent(err) {
+		t.Errorf("IsPermanent(err) = false, want true")
+	}
+	if count != 1 {
+		t.Errorf("expected 1 attempt, but got %d", count)
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []RetryOption
+		errs      []error
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:      "default_no_retry",
+			errs:      []error{errInternal, nil},
+			wantCount: 1,
+			wantErr:   errInternal,
+		},
+		{
+			name:      "success_after_retries",
+			opts:      []RetryOption{Attempts(5), Delay(time.Millisecond)},
+			errs:      []error{errInternal, errInternal, nil},
+			wantCount: 3,
+			wantErr:   nil,
+		},
+		{
+			name:      "last_error",
+			opts:      []RetryOption{Attempts(2), Delay(time.Millisecond)},
+			errs:      []error{errInternal, errInvalid, nil},
+			wantCount: 2,
+			wantErr:   errInvalid,
+		},
+		{
+			name: "retry_if",
+			opts: []RetryOption{Attempts(5), Delay(time.Millisecond), RetryIf(func(err error) bool {
+				return !errors.Is(err, errInvalid)
+			})},
+			errs:      []error{errInternal, errInvalid, nil},
+			wantCount: 2,
+			wantErr:   errInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var count int
+			err := newRetryPolicy(tc.opts).do(context.Background(), func() error {
+				count++
+				return tc.errs[count-1]
+			})
+			if !errors.Is(err, tc.wantErr) || (err == nil) != (tc.wantErr == nil) {
+				t.Errorf("do() = %v, want %v", err, tc.wantErr)
+			}
+			if count != tc.wantCount {
+				t.Errorf("expected %d attempts, but got %d", tc.wantCount, count)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Do_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var count int
+	p := newRetryPolicy([]RetryOption{Attempts(5), Delay(time.Hour)})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	err := p.do(ctx, func() error {
+		count++
+		return errInternal
+	})
+	if !errors.Is(err, errInternal) {
+		t.Errorf("do() = %v, want the last error %v", err, errInternal)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 attempt, but got %d", count)
+	}
+
+	if err := p.do(ctx, func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("do() on a canceled context = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRetryPolicy_Do_Deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var count int
+	p := newRetryPolicy([]RetryOption{Attempts(5), Delay(time.Hour)})
+	start := time.Now()
+	err := p.do(ctx, func() error {
+		count++
+		return errInternal
+	})
+	if !errors.Is(err, errInternal) {
+		t.Errorf("do() = %v, want the last error %v", err, errInternal)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 attempt, but got %d", count)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("do() waited %v for a retry past the deadline", elapsed)
+	}
+}
+
+func TestRetryPolicy_Do_RetryAfter(t *testing.T) {
+	var delays []time.Duration
+	p := newRetryPolicy([]RetryOption{Attempts(2), Delay(time.Hour)})
+	p.onRetry = func(_ error, delay time.Duration) {
+		delays = append(delays, delay)
+	}
+
+	var count int
+	err := p.do(context.Background(), func() error {
+		count++
+		if count == 1 {
+			return fmt.Errorf("rate limited: %w", retryAfterError{after: time.Millisecond})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("do() = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(delays, []time.Duration{time.Millisecond}) {
+		t.Errorf("expected a retry after [1ms], but got %v", delays)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := newRetryPolicy([]RetryOption{Delay(time.Second), MaxJitter(0), MaxDelay(5 * time.Second)})
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := p.backoff(attempt + 1); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt+1, got, want)
+		}
+	}
+
+	p = newRetryPolicy([]RetryOption{Delay(time.Second), MaxJitter(0), DelayType(FixedDelay)})
+	if got := p.backoff(10); got != time.Second {
+		t.Errorf("backoff(10) = %v, want %v", got, time.Second)
+	}
+
+	p = newRetryPolicy([]RetryOption{Delay(time.Second), MaxJitter(0)})
+	if got := p.backoff(1000); got <= 0 {
+		t.Errorf("backoff(1000) = %v, want a positive delay", got)
+	}
+}
+
+func TestGroup_WithRetryBudget(t *testing.T) {
+	ctx, g := New(context.Background(), Collect,
+		WithRetry(Attempts(5), Delay(time.Millisecond)),
+		WithRetryBudget(6),
+	)
+
+	var count int32
+	for i := 0; i < 10; i++ {
+		g.Go(ctx, func() error {
+			atomic.AddInt32(&count, 1)
+			return errInternal
+		})
+	}
+	g.Wait()
+
+	// 10 first attempts and 6 retries.
+	if count != 16 {
+		t.Errorf("expected 16 attempts, but got %d", count)
+	}
+}
+
+func TestGroup_WithOnRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts []int
+	)
+	ctx, g := New(context.Background(), Collect,
+		WithRetry(Attempts(3), Delay(time.Millisecond), MaxJitter(0), DelayType(FixedDelay)),
+		WithOnRetry(func(info TaskInfo, err error, delay time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			if info.Name != "flaky" || !errors.Is(err, errInternal) || delay != time.Millisecond {
+				t.Errorf("unexpected retry of %q after %v in %v", info.Name, err, delay)
+			}
+			attempts = append(attempts, info.Attempts)
+		}),
+	)
+
+	g.GoNamed(ctx, "flaky", func() error {
+		return errInternal
+	})
+	g.Wait()
+
+	if !reflect.DeepEqual(attempts, []int{1, 2}) {
+		t.Errorf("expected retries after attempts [1 2], but got %v", attempts)
+	}
+}
+
+func TestGroup_WithBackoff(t *testing.T) {
+	tests := []struct {
+		name      string
+		opt       Option
+		taskOpts  []TaskOption
+		wantCount int32
+	}{
+		{
+			name:      "exponential",
+			opt:       WithExponentialBackoff(time.Millisecond, 2*time.Millisecond, 3),
+			wantCount: 3,
+		},
+		{
+			name:      "constant",
+			opt:       WithConstantBackoff(time.Millisecond, 4),
+			wantCount: 4,
+		},
+		{
+			name:      "task_override",
+			opt:       WithConstantBackoff(time.Millisecond, 4),
+			taskOpts:  []TaskOption{TaskRetry(Attempts(2))},
+			wantCount: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, g := New(context.Background(), Collect, tc.opt)
+
+			var count int32
+			g.Go(ctx, func() error {
+				atomic.AddInt32(&count, 1)
+				return errInternal
+			}, tc.taskOpts...)
+			g.Wait()
+
+			if count != tc.wantCount {
+				t.Errorf("expected %d attempts, but got %d", tc.wantCount, count)
+			}
+		})
+	}
+}
+
+func TestGroup_WithRetryRateLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect,
+		WithRetry(Attempts(2), Delay(time.Millisecond), MaxJitter(0)),
+		WithRetryRateLimit(100, time.Second),
+	)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		g.Go(ctx, func() error {
+			return errInternal
+		})
+	}
+	g.Wait()
+
+	// 10 retries spaced out by 10ms take at least 90ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected retries to be rate limited, but took %v", elapsed)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/sadlil/workgroup/retrygo
+
+go 1.23.1
+
+require (
+	github.com/avast/retry-go v3.0.0+incompatible
+	github.com/sadlil/workgroup v0.0.0
+)
+
+require github.com/stretchr/testify v1.9.0 // indirect
+
+replace github.com/sadlil/workgroup => ../
@@ -0,0 +1,44 @@
+// Package retrygo adapts the options of the github.com/avast/retry-go
+// library to the retry policy of a workgroup.
+//
+// The workgroup package ships its own retry engine and no longer depends
+// on retry-go. This package eases the migration of existing code that
+// configures retries with retry-go options:
+//
+//	ctx, g := workgroup.New(ctx, workgroup.Collect, workgroup.WithRetry(
+//		retrygo.Options(retry.Attempts(3), retry.Delay(time.Second)),
+//	))
+package retrygo
+
+import (
+	"context"
+
+	"github.com/avast/retry-go"
+	"github.com/sadlil/workgroup"
+)
+
+// Options returns a workgroup retry option that executes tasks with
+// retry.Do, configured with opts.
+//
+// As in previous versions of the workgroup package, tasks are attempted
+// only once unless opts raise the number of attempts, only the error of
+// the last attempt is returned, and retries stop once the workgroup
+// context is done. Errors marked with workgroup.Permanent are not
+// retried. Other workgroup retry options have no effect when used along
+// with Options.
+func Options(opts ...retry.Option) workgroup.RetryOption {
+	return workgroup.Retrier(func(ctx context.Context, fn func() error) error {
+		defaults := []retry.Option{
+			retry.Attempts(1),
+			retry.LastErrorOnly(true),
+			retry.Context(ctx),
+		}
+		return retry.Do(func() error {
+			err := fn()
+			if workgroup.IsPermanent(err) {
+				return retry.Unrecoverable(err)
+			}
+			return err
+		}, append(defaults, opts...)...)
+	})
+}
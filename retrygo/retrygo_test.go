@@ -0,0 +1,62 @@
+package retrygo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/sadlil/workgroup"
+)
+
+var errInternal = errors.New("internal")
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []retry.Option
+		fn        func() error
+		wantCount int32
+	}{
+		{
+			name:      "retry",
+			opts:      []retry.Option{retry.Attempts(3), retry.Delay(time.Millisecond)},
+			fn:        func() error { return errInternal },
+			wantCount: 3,
+		},
+		{
+			name:      "no_retry_by_default",
+			opts:      nil,
+			fn:        func() error { return errInternal },
+			wantCount: 1,
+		},
+		{
+			name:      "permanent",
+			opts:      []retry.Option{retry.Attempts(3), retry.Delay(time.Millisecond)},
+			fn:        func() error { return workgroup.Permanent(errInternal) },
+			wantCount: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, g := workgroup.New(context.Background(), workgroup.Collect, workgroup.WithRetry(Options(tc.opts...)))
+
+			var count int32
+			g.Go(ctx, func() error {
+				atomic.AddInt32(&count, 1)
+				return tc.fn()
+			})
+
+			err := g.Wait()
+			if !errors.Is(err, errInternal) {
+				t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+			}
+			if count != tc.wantCount {
+				t.Errorf("expected %d attempts, but got %d", tc.wantCount, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package workgroup
+
+import (
+	"context"
+	"iter"
+)
+
+// GoSeq launches a goroutine within the workgroup running fn for every
+// value of seq, and blocks until all values have been launched. Values
+// are pulled lazily from seq as goroutines are admitted, so that the
+// concurrency limit applies backpressure on seq, which needs not be
+// materialized as a slice. fn receives the context of its task, see
+// GoCtx.
+//
+// Like GoErr, GoSeq stops pulling values and returns the cause of the
+// cancellation once ctx or the context of the workgroup is done. It
+// returns nil once seq is exhausted.
+func GoSeq[T any](ctx context.Context, g *Group, seq iter.Seq[T], fn func(ctx context.Context, v T) error, opts ...TaskOption) error {
+	for v := range seq {
+		t := g.newTaskContext(func(ctx context.Context) error {
+			return fn(ctx, v)
+		}, opts)
+		if err := g.launchErr(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
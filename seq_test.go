@@ -0,0 +1,73 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGoSeq(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	var pulled, running, peak atomic.Int32
+	seq := func(yield func(int) bool) {
+		for i := range 10 {
+			pulled.Add(1)
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	err := GoSeq(ctx, g, seq, func(ctx context.Context, v int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if v == 5 {
+			return errInternal
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("GoSeq() = %v, want nil", err)
+	}
+
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if n := pulled.Load(); n != 10 {
+		t.Errorf("expected 10 values to be pulled, but got %d", n)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 goroutines to run concurrently, but got %d", p)
+	}
+}
+
+func TestGoSeq_Canceled(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast, WithLimit(1))
+
+	var pulled atomic.Int32
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled.Add(1)
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	err := GoSeq(ctx, g, seq, func(ctx context.Context, v int) error {
+		return errInternal
+	})
+	if !errors.Is(err, errInternal) {
+		t.Errorf("GoSeq() = %v, want %v", err, errInternal)
+	}
+	g.Wait()
+	if n := pulled.Load(); n > 2 {
+		t.Errorf("expected the values to stop being pulled, but got %d", n)
+	}
+}
@@ -0,0 +1,68 @@
+package workgroup
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// WithShutdownSignals gracefully shuts the workgroup down when one of the
+// given signals is received, os.Interrupt and syscall.SIGTERM if none are
+// given: the workgroup stops accepting new goroutines and closes the
+// channel returned by Stopping, as with Shutdown. The goroutines still
+// running after the grace period, or once a second signal is received,
+// are canceled through the context of the workgroup with a *SignalError
+// as cause. A grace period of zero or less waits for the second signal.
+//
+// It replaces the signal.NotifyContext and drain boilerplate of main
+// functions. The signals are handled until the workgroup has finished.
+func WithShutdownSignals(grace time.Duration, sigs ...os.Signal) Option {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return func(g *Group) {
+		g.signals = &shutdownSignals{grace: grace, sigs: sigs}
+	}
+}
+
+// shutdownSignals configures the signals shutting a workgroup down.
+type shutdownSignals struct {
+	grace time.Duration
+	sigs  []os.Signal
+}
+
+// notify handles the shutdown signals until the context of the workgroup
+// is done. g.errLock must be held, or g must not be shared yet.
+func (g *Group) notify() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, g.signals.sigs...)
+	done := g.context().Done()
+
+	go func() {
+		defer signal.Stop(ch)
+
+		var sig os.Signal
+		select {
+		case sig = <-ch:
+		case <-done:
+			return
+		}
+		ctx, cancel := context.WithCancelCause(context.Background())
+		go g.shutdown(ctx)
+
+		var timeout <-chan time.Time
+		if g.signals.grace > 0 {
+			timer := g.clock().NewTimer(g.signals.grace)
+			defer timer.Stop()
+			timeout = timer.C()
+		}
+		select {
+		case sig = <-ch:
+		case <-timeout:
+		case <-done:
+		}
+		cancel(&SignalError{Signal: sig})
+	}()
+}
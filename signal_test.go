@@ -0,0 +1,96 @@
+//go:build unix
+
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithShutdownSignals(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithShutdownSignals(0, syscall.SIGUSR1))
+
+	stopped := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-g.Stopping()
+		close(stopped)
+		return nil
+	})
+	g.Go(ctx, func() error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("expected the first signal to shut the workgroup down")
+	}
+	if err := g.GoErr(ctx, func() error { return nil }); !errors.Is(err, ErrDraining) {
+		t.Errorf("group.GoErr() = %v, want %v", err, ErrDraining)
+	}
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	var sigErr *SignalError
+	if err := g.Wait(); !errors.As(err, &sigErr) || sigErr.Signal != syscall.SIGUSR1 {
+		t.Errorf("group.Wait() = %v, want a *SignalError for %v", err, syscall.SIGUSR1)
+	}
+}
+
+func TestGroup_WithShutdownSignals_Grace(t *testing.T) {
+	// The timers of the fake clock fire immediately, ending the grace
+	// period right after the signal.
+	ctx, g := New(context.Background(), Collect, WithClock(&fakeClock{}), WithShutdownSignals(time.Minute, syscall.SIGUSR1))
+
+	g.Go(ctx, func() error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	var sigErr *SignalError
+	if err := g.Wait(); !errors.As(err, &sigErr) {
+		t.Errorf("group.Wait() = %v, want a *SignalError", err)
+	}
+}
+
+func TestCollector_WithShutdownSignals(t *testing.T) {
+	c := NewCollector(WithShutdownSignals(0, syscall.SIGUSR1))
+
+	// Keeps the signal from terminating the test if the collector does
+	// not handle it, as expected.
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	defer signal.Stop(ch)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	select {
+	case <-c.g.Stopping():
+		t.Fatal("expected the signal not to shut the collector down")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	var ran atomic.Bool
+	c.Go(func() error {
+		ran.Store(true)
+		return nil
+	})
+	if err := c.Wait(); err != nil || !ran.Load() {
+		t.Errorf("collector.Wait() = %v with ran = %v, want the signal to be ignored", err, ran.Load())
+	}
+}
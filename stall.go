@@ -0,0 +1,154 @@
+package workgroup
+
+import (
+	"bytes"
+	"cmp"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// StallInfo describes a goroutine of a workgroup that has been running for
+// longer than the stall timeout, see WithStallTimeout.
+type StallInfo struct {
+	TaskInfo
+	// Running is the time elapsed since the goroutine started executing.
+	Running time.Duration
+	// Stack is the stack trace of the goroutine when the stall was
+	// detected, if it could be captured.
+	Stack []byte
+}
+
+// WithStallTimeout sets up a watchdog invoking onStall for every goroutine
+// of the workgroup that has been running for longer than d, including its
+// retries, along with its stack trace. It makes a silent hang in one of
+// hundreds of goroutines diagnosable. Each goroutine is reported at most
+// once, and the watchdog does not cancel it.
+//
+// The watchdog checks the running goroutines every d/2, and onStall is
+// called from its goroutine, so it must not block for long.
+func WithStallTimeout(d time.Duration, onStall func(StallInfo)) Option {
+	return func(g *Group) {
+		g.watch(&watchdog{timeout: d, stacks: true, report: onStall})
+		g.stacks = true
+	}
+}
+
+// WithSlowTaskThreshold invokes onSlow for every goroutine of the
+// workgroup that has been running for longer than d, with its current
+// attempt and how long it has been running. It is a cheaper alternative
+// to WithStallTimeout, as it does not capture stack traces, which catches
+// the goroutine that is a hundred times slower than the others.
+func WithSlowTaskThreshold(d time.Duration, onSlow func(info TaskInfo, running time.Duration)) Option {
+	return func(g *Group) {
+		g.watch(&watchdog{timeout: d, report: func(info StallInfo) {
+			onSlow(info.TaskInfo, info.Running)
+		}})
+	}
+}
+
+// watchdog reports goroutines running for longer than its timeout.
+type watchdog struct {
+	timeout time.Duration
+	// stacks is whether the stack traces of the goroutines are reported.
+	stacks bool
+	report func(StallInfo)
+}
+
+// watch adds w to the watchdogs of the workgroup, which are kept sorted
+// by timeout, so that a goroutine is reported by them in order.
+func (g *Group) watch(w *watchdog) {
+	i, _ := slices.BinarySearchFunc(g.watchdogs, w.timeout, func(w *watchdog, d time.Duration) int {
+		return cmp.Compare(w.timeout, d)
+	})
+	g.watchdogs = slices.Insert(g.watchdogs, i, w)
+}
+
+// watchStalls checks for stalled goroutines until stop is closed.
+func (g *Group) watchStalls(stop <-chan struct{}) {
+	interval := g.watchdogs[0].timeout / 2
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	for {
+		timer := g.clock().NewTimer(interval)
+		select {
+		case <-timer.C():
+		case <-stop:
+			timer.Stop()
+			return
+		}
+		g.checkStalls()
+	}
+}
+
+// stall is a goroutine to report to a watchdog.
+type stall struct {
+	w *watchdog
+	t *task
+}
+
+// checkStalls reports the goroutines that have just exceeded the timeout
+// of a watchdog.
+func (g *Group) checkStalls() {
+	now := g.clock().Now()
+	var stalls []stall
+
+	g.errLock.Lock()
+	for t := range g.active {
+		for t.reported < len(g.watchdogs) && now.Sub(t.start) >= g.watchdogs[t.reported].timeout {
+			stalls = append(stalls, stall{w: g.watchdogs[t.reported], t: t})
+			t.reported++
+		}
+	}
+	g.errLock.Unlock()
+
+	var stacks map[uint64][]byte
+	for _, s := range stalls {
+		info := StallInfo{
+			TaskInfo: s.t.info(),
+			Running:  now.Sub(s.t.start),
+		}
+		if s.w.stacks {
+			if stacks == nil {
+				stacks = goroutineStacks()
+			}
+			info.Stack = stacks[s.t.goroutine]
+		}
+		s.w.report(info)
+	}
+}
+
+// goroutineID returns the ID of the current goroutine, as printed in its
+// stack trace.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	b, _, _ = bytes.Cut(b, []byte(" "))
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// goroutineStacks returns the stack traces of all goroutines by ID.
+func goroutineStacks() map[uint64][]byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[uint64][]byte)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		b := bytes.TrimPrefix(stack, []byte("goroutine "))
+		b, _, _ = bytes.Cut(b, []byte(" "))
+		if id, err := strconv.ParseUint(string(b), 10, 64); err == nil {
+			stacks[id] = stack
+		}
+	}
+	return stacks
+}
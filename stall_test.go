@@ -0,0 +1,85 @@
+package workgroup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithStallTimeout(t *testing.T) {
+	stalls := make(chan StallInfo, 10)
+	ctx, g := New(context.Background(), Collect, WithStallTimeout(20*time.Millisecond, func(info StallInfo) {
+		stalls <- info
+	}))
+
+	release := make(chan struct{})
+	g.GoNamed(ctx, "fast", func() error {
+		return nil
+	})
+	g.GoNamed(ctx, "stuck", func() error {
+		<-release
+		return nil
+	})
+
+	var info StallInfo
+	select {
+	case info = <-stalls:
+	case <-time.After(time.Second):
+		t.Fatal("expected the stuck goroutine to be reported")
+	}
+	if info.Name != "stuck" {
+		t.Errorf("expected the stuck goroutine to be reported, but got %q", info.Name)
+	}
+	if info.Running < 20*time.Millisecond {
+		t.Errorf("expected the goroutine to be running for at least 20ms, but got %v", info.Running)
+	}
+	if !bytes.Contains(info.Stack, []byte("TestGroup_WithStallTimeout")) {
+		t.Errorf("expected the stack of the stuck goroutine, but got:\n%s", info.Stack)
+	}
+
+	// Each goroutine is reported once.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	g.Wait()
+	if n := len(stalls); n != 0 {
+		t.Errorf("expected the stuck goroutine to be reported once, but got %d more reports", n)
+	}
+}
+
+func TestGroup_WithSlowTaskThreshold(t *testing.T) {
+	type slow struct {
+		info    TaskInfo
+		running time.Duration
+	}
+	slows := make(chan slow, 10)
+	stalls := make(chan StallInfo, 10)
+	ctx, g := New(context.Background(), Collect,
+		WithStallTimeout(time.Hour, func(info StallInfo) {
+			stalls <- info
+		}),
+		WithSlowTaskThreshold(20*time.Millisecond, func(info TaskInfo, running time.Duration) {
+			slows <- slow{info: info, running: running}
+		}),
+	)
+
+	release := make(chan struct{})
+	g.GoNamed(ctx, "slow", func() error {
+		<-release
+		return nil
+	})
+
+	select {
+	case s := <-slows:
+		if s.info.Name != "slow" || s.info.Attempts != 1 || s.running < 20*time.Millisecond {
+			t.Errorf("unexpected report of the slow goroutine: %+v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the slow goroutine to be reported")
+	}
+	close(release)
+	g.Wait()
+	if len(stalls) != 0 {
+		t.Errorf("expected the slow goroutine not to be reported as stalled")
+	}
+}
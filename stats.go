@@ -0,0 +1,76 @@
+package workgroup
+
+import "time"
+
+// Stats is a snapshot of the state of a workgroup, see Group.Stats.
+type Stats struct {
+	// Submitted is the number of goroutines launched so far.
+	Submitted int
+	// Pending is the number of goroutines accepted but not started yet,
+	// see Group.Pending.
+	Pending int
+	// Running is the number of goroutines currently executing.
+	Running int
+	// Detached is the number of goroutines launched with GoDetached
+	// currently executing.
+	Detached int
+	// Completed is the number of goroutines that have finished, i.e. the
+	// sum of Succeeded, Failed and Ignored.
+	Completed int
+	// Succeeded is the number of goroutines that succeeded.
+	Succeeded int
+	// Failed is the number of goroutines that failed, including those
+	// whose errors were not retained.
+	Failed int
+	// Suppressed is the number of errors that were not retained, see
+	// Group.Suppressed.
+	Suppressed int
+	// Ignored is the number of goroutines whose errors were ignored, see
+	// WithIgnoreErrors and WithErrorMapper.
+	Ignored int
+	// Retried is the total number of retries across all goroutines.
+	Retried int
+	// Elapsed is the time elapsed since the workgroup was created, until
+	// Wait returned if it has.
+	Elapsed time.Duration
+}
+
+// Stats returns a snapshot of the counters of the workgroup, e.g. to
+// report the progress of a long batch job on a dashboard without
+// instrumenting every function. It is safe to call at any time.
+func (g *Group) Stats() Stats {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.stats()
+}
+
+// stats returns the counters of the workgroup. g.errLock must be held.
+func (g *Group) stats() Stats {
+	s := Stats{
+		Submitted:  g.submitted,
+		Pending:    int(g.pending.Load()),
+		Running:    int(g.running.Load()),
+		Detached:   int(g.detached.Load()),
+		Completed:  g.successes + g.failures + g.ignored,
+		Succeeded:  g.successes,
+		Failed:     g.failures,
+		Suppressed: g.dropped,
+		Ignored:    g.ignored,
+		Retried:    int(g.retries.Load()),
+		Elapsed:    g.elapsed(),
+	}
+	return s
+}
+
+// elapsed returns the time elapsed since the workgroup was created, until
+// it finished, if it has. g.errLock must be held.
+func (g *Group) elapsed() time.Duration {
+	switch {
+	case g.start.IsZero():
+		return 0
+	case g.finished:
+		return g.end.Sub(g.start)
+	default:
+		return g.clock().Now().Sub(g.start)
+	}
+}
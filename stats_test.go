@@ -0,0 +1,54 @@
+package workgroup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroup_Stats(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx, g := New(context.Background(), Collect,
+		WithClock(clock),
+		WithRetry(Attempts(3), Delay(time.Second), MaxJitter(0)),
+	)
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+	waitFor(t, func() bool { return g.Running() == 1 })
+	if s := g.Stats(); s.Submitted != 1 || s.Running != 1 || s.Completed != 0 {
+		t.Errorf("expected 1 submitted and running goroutine, but got %+v", s)
+	}
+
+	var attempts int
+	g.Go(ctx, func() error {
+		attempts++
+		if attempts < 3 {
+			return errInternal
+		}
+		return nil
+	})
+	g.Go(ctx, func() error {
+		return errInvalid
+	}, TaskRetry(Attempts(1)))
+	close(release)
+	g.Wait()
+
+	s := g.Stats()
+	want := Stats{Submitted: 3, Completed: 3, Succeeded: 2, Failed: 1, Retried: 2, Elapsed: s.Elapsed}
+	if s != want {
+		t.Errorf("group.Stats() = %+v, want %+v", s, want)
+	}
+	// 1s + 2s of backoff.
+	if s.Elapsed < 3*time.Second {
+		t.Errorf("expected an elapsed time of at least 3s, but got %v", s.Elapsed)
+	}
+
+	clock.advance(time.Hour)
+	if elapsed := g.Stats().Elapsed; elapsed != s.Elapsed {
+		t.Errorf("expected the elapsed time to stop at Wait, but got %v, want %v", elapsed, s.Elapsed)
+	}
+}
@@ -0,0 +1,121 @@
+package workgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// Stream is a typed workgroup that emits the values produced by its
+// tasks strictly in submission order, while still executing the tasks in
+// parallel.
+//
+// Values of tasks that complete out of order are buffered internally
+// until all tasks submitted before them have completed. Tasks that fail
+// are skipped in the stream; their errors are reported by Wait according
+// to the failure mode of the stream.
+type Stream[T any] struct {
+	g *Group
+
+	mu        sync.Mutex
+	submitted int
+	next      int
+	pending   map[int]streamItem[T]
+
+	out       chan T
+	closeOnce sync.Once
+}
+
+type streamItem[T any] struct {
+	value T
+	ok    bool
+}
+
+// NewStream creates a new Stream with the specified failure mode and
+// options. It returns a context that is derived from `ctx`, following the
+// same rules as New.
+func NewStream[T any](ctx context.Context, mode FailureMode, opts ...Option) (context.Context, *Stream[T]) {
+	ctx, g := New(ctx, mode, opts...)
+	return ctx, &Stream[T]{
+		g:       g,
+		pending: make(map[int]streamItem[T]),
+		out:     make(chan T),
+	}
+}
+
+// Go launches a new goroutine within the stream. Its value is emitted on
+// the Results channel after the values of all previously submitted
+// goroutines. It follows the same retry policy and concurrency limit as
+// Group.Go, and accepts the same task options.
+func (s *Stream[T]) Go(ctx context.Context, fn func() (T, error), opts ...TaskOption) {
+	s.mu.Lock()
+	i := s.submitted
+	s.submitted++
+	s.mu.Unlock()
+
+	var (
+		value T
+		ok    bool
+	)
+	t := s.g.newTask(func() error {
+		v, err := fn()
+		if err != nil {
+			ok = false
+			return err
+		}
+		value, ok = v, true
+		return nil
+	}, opts)
+	t.then = func(error) {
+		// Tasks whose errors are ignored by the workgroup are skipped as
+		// well.
+		s.emit(i, value, ok)
+	}
+	s.g.launch(ctx, t)
+}
+
+// Results returns the channel on which values are emitted in submission
+// order. The channel is closed once Wait has returned.
+//
+// Results must be consumed concurrently with Wait: goroutines block until
+// their values have been received, which also applies backpressure on
+// the stream.
+func (s *Stream[T]) Results() <-chan T {
+	return s.out
+}
+
+// Wait blocks until all goroutines in the stream have completed and
+// their values have been emitted, then closes the Results channel. It
+// returns the error of the stream according to its failure mode.
+func (s *Stream[T]) Wait() error {
+	err := s.g.Wait()
+	s.closeOnce.Do(func() {
+		close(s.out)
+	})
+	return err
+}
+
+// Cancel cancels the stream context, signaling all running goroutines to
+// stop.
+func (s *Stream[T]) Cancel() {
+	s.g.Cancel()
+}
+
+// emit buffers the outcome of the i-th goroutine and sends all values
+// that are now in order.
+func (s *Stream[T]) emit(i int, v T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[i] = streamItem[T]{value: v, ok: ok}
+	for {
+		item, found := s.pending[s.next]
+		if !found {
+			return
+		}
+		delete(s.pending, s.next)
+		s.next++
+		if item.ok {
+			s.out <- item.value
+		}
+	}
+}
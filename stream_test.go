@@ -0,0 +1,48 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	ctx, s := NewStream[int](context.Background(), Collect, WithLimit(4))
+
+	var got []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := range s.Results() {
+			got = append(got, v)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		s.Go(ctx, func() (int, error) {
+			// Later submissions finish first.
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			if i == 5 {
+				return 0, errInternal
+			}
+			return i, nil
+		})
+	}
+
+	err := s.Wait()
+	<-done
+	if !errors.Is(err, errInternal) {
+		t.Errorf("stream.Wait() = %v, want %v", err, errInternal)
+	}
+
+	want := []int{0, 1, 2, 3, 4, 6, 7, 8, 9}
+	if len(got) != len(want) {
+		t.Fatalf("stream emitted %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("stream emitted %v, want %v", got, want)
+		}
+	}
+}
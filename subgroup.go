@@ -0,0 +1,46 @@
+package workgroup
+
+import "context"
+
+// SubGroup creates a child workgroup for a nested fan-out, with the given
+// failure mode and options. The returned context is derived from the
+// context of g, so canceling g cancels the goroutines of the subgroup.
+//
+// The subgroup counts as a single goroutine of g: once Wait of the
+// subgroup has returned, its error is recorded in g like the error of any
+// goroutine, and g waits for it. Wait must therefore be called on the
+// subgroup. This goroutine does not take a slot of the concurrency limits
+// of g, so that nested fan-outs cannot deadlock on them.
+//
+// With WithSharedLimit, the goroutines of the subgroup count towards the
+// concurrency limit of g instead of a limit of their own.
+func (g *Group) SubGroup(mode FailureMode, opts ...Option) (context.Context, *Group) {
+	ctx, sub := New(g.context(), mode, opts...)
+	if sub.sharedLimit {
+		sub.limiter = g.admission()
+	}
+	waited := make(chan struct{})
+	sub.waited = waited
+
+	t := g.newTask(func() error {
+		<-waited
+		return sub.Wait()
+	}, []TaskOption{TaskRetry(Attempts(1))})
+	if err := g.accepting(); err != nil {
+		g.reject(t, err)
+		return ctx, sub
+	}
+	g.emitSubmit(t)
+	g.run(t, g.admit(nil))
+	return ctx, sub
+}
+
+// WithSharedLimit makes the goroutines of a subgroup count towards the
+// concurrency limit of its parent, see SubGroup, so that a hierarchy of
+// workgroups is bounded as a whole. The limit can then only be changed
+// on the parent. It has no effect on workgroups created with New.
+func WithSharedLimit() Option {
+	return func(g *Group) {
+		g.sharedLimit = true
+	}
+}
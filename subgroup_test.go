@@ -0,0 +1,86 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroup_SubGroup(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	g.Go(ctx, func() error {
+		return nil
+	})
+	subCtx, sub := g.SubGroup(Collect)
+	for i := 0; i < 3; i++ {
+		sub.Go(subCtx, func() error {
+			return errInternal
+		})
+	}
+	if err := sub.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("sub.Wait() = %v, want %v", err, errInternal)
+	}
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	// The subgroup counts as a single failed goroutine.
+	if s := g.Stats(); s.Succeeded != 1 || s.Failed != 1 {
+		t.Errorf("expected 1 succeeded and 1 failed goroutine, but got %+v", s)
+	}
+}
+
+func TestGroup_SubGroup_Cancel(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast)
+
+	subCtx, sub := g.SubGroup(Collect)
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+	sub.Go(subCtx, func() error {
+		<-subCtx.Done()
+		return nil
+	})
+	sub.Wait()
+
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+}
+
+func TestGroup_SubGroup_SharedLimit(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	var running, peak atomic.Int32
+	work := func() error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		return nil
+	}
+
+	g.Go(ctx, work)
+	for i := 0; i < 2; i++ {
+		subCtx, sub := g.SubGroup(Collect, WithSharedLimit())
+		for j := 0; j < 10; j++ {
+			sub.Go(subCtx, work)
+		}
+		if err := sub.Wait(); err != nil {
+			t.Fatalf("sub.Wait() = %v", err)
+		}
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v", err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 goroutines running concurrently, but got %d", p)
+	}
+}
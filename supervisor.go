@@ -0,0 +1,246 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// RestartStrategy defines which services are restarted when a service
+// launched with GoService fails.
+type RestartStrategy int
+
+const (
+	// OneForOne only restarts the failed service. It is the default.
+	OneForOne RestartStrategy = iota
+	// OneForAll stops all services of the workgroup when one fails, by
+	// canceling the context passed to them, waits for all of them to
+	// return, and restarts them all. It suits interdependent services.
+	OneForAll
+)
+
+// WithRestartStrategy sets which services are restarted when a service
+// fails, see GoService. The restart policy of the failed service decides
+// whether and when the services are restarted; the restarts of the
+// stopped siblings do not count towards their own policy.
+func WithRestartStrategy(strategy RestartStrategy) Option {
+	return func(g *Group) {
+		g.supervisor = nil
+		if strategy == OneForAll {
+			g.supervisor = newSupervisor()
+		}
+	}
+}
+
+// GiveUpAction defines what a supervised service does once it has been
+// restarted too often, see RestartPolicy.
+type GiveUpAction int
+
+const (
+	// GiveUpFail reports the last error of the service as the error of
+	// its goroutine.
+	GiveUpFail GiveUpAction = iota
+	// GiveUpCancel reports the last error of the service and cancels the
+	// workgroup with it as the cause.
+	GiveUpCancel
+	// GiveUpIgnore stops the service without reporting an error.
+	GiveUpIgnore
+)
+
+// RestartPolicy defines how a service launched with GoService is
+// restarted when it fails.
+type RestartPolicy struct {
+	// MaxRestarts is the maximum number of restarts within Window, after
+	// which the service gives up. Zero never restarts the service, a
+	// negative value restarts it indefinitely.
+	MaxRestarts int
+	// Window is the period over which restarts are counted. Zero counts
+	// them over the lifetime of the service.
+	Window time.Duration
+	// Backoff is the delay before the first restart, doubled for every
+	// further restart within Window.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between restarts. Zero disables the cap.
+	MaxBackoff time.Duration
+	// GiveUp is the behavior of the service once it gives up.
+	GiveUp GiveUpAction
+}
+
+// GoService launches a long-running service within the workgroup under
+// supervision: whenever fn fails or panics, it is restarted according to
+// policy, until it returns nil, the context of the workgroup is done, or
+// the service gives up.
+//
+// The service counts as a single goroutine towards the concurrency limit,
+// and is not retried by the retry policy of the workgroup. fn receives
+// the context of the task, derived from the context of the workgroup.
+func (g *Group) GoService(ctx context.Context, name string, fn func(ctx context.Context) error, policy RestartPolicy) {
+	var t *task
+	t = g.newTaskContext(func(ctx context.Context) error {
+		return g.supervise(ctx, t, fn, policy)
+	}, []TaskOption{TaskRetry(Attempts(1))})
+	t.name = name
+	g.launch(ctx, t)
+}
+
+// supervise runs fn of t with gctx, restarting it according to policy.
+func (g *Group) supervise(gctx context.Context, t *task, fn func(ctx context.Context) error, policy RestartPolicy) error {
+	clock := g.clock()
+	sup := g.supervisor
+
+	var restarts []time.Time
+	for {
+		ctx, gen := gctx, gctx
+		var release func()
+		if sup != nil {
+			// The generation is shared by all services, so it must not
+			// carry the values of the task of one of them.
+			gen = sup.enter(g.context())
+			ctx, release = generation(gctx, gen)
+		}
+		err := g.safeCall(ctx, t, fn)
+		if release != nil {
+			release()
+		}
+		if sup != nil && sup.exit(gen) && gctx.Err() == nil {
+			// Stopped to restart all services.
+			continue
+		}
+		if err == nil || gctx.Err() != nil {
+			return err
+		}
+
+		now := clock.Now()
+		if policy.Window > 0 {
+			for len(restarts) > 0 && now.Sub(restarts[0]) >= policy.Window {
+				restarts = restarts[1:]
+			}
+		}
+		if policy.MaxRestarts >= 0 && len(restarts) >= policy.MaxRestarts {
+			switch policy.GiveUp {
+			case GiveUpCancel:
+				g.cancelCause(err)
+			case GiveUpIgnore:
+				return nil
+			}
+			return err
+		}
+		restarts = append(restarts, now)
+
+		var canceled bool
+		backoff := func() {
+			timer := clock.NewTimer(restartDelay(policy, len(restarts)))
+			select {
+			case <-timer.C():
+			case <-gctx.Done():
+				timer.Stop()
+				canceled = true
+			}
+		}
+		if sup != nil {
+			sup.restart(gen, backoff)
+		} else {
+			backoff()
+		}
+		if canceled {
+			return err
+		}
+	}
+}
+
+// generation returns a context derived from the context ctx of a
+// service, which is also canceled along with the context gen of its
+// generation, with the same cause, and the function releasing it.
+func generation(ctx, gen context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(gen, func() {
+		cancel(context.Cause(gen))
+	})
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// restartDelay returns the delay before the nth restart within the
+// window.
+func restartDelay(policy RestartPolicy, n int) time.Duration {
+	d := BackOffDelay(n, policy.Backoff)
+	if policy.MaxBackoff > 0 && d > policy.MaxBackoff {
+		d = policy.MaxBackoff
+	}
+	return d
+}
+
+// errRestart is the cause of the cancellation of the services stopped to
+// restart all services.
+var errRestart = errors.New("workgroup: restarting all services")
+
+// supervisor coordinates the services of a workgroup with the OneForAll
+// strategy. The services run in generations sharing a context, which is
+// canceled to restart them all.
+type supervisor struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	running    int
+	restarting bool
+}
+
+func newSupervisor() *supervisor {
+	s := &supervisor{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// enter registers a service as running and returns the context of the
+// current generation, waiting for a restart in progress to complete.
+func (s *supervisor) enter(parent context.Context) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.restarting {
+		s.cond.Wait()
+	}
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancelCause(parent)
+	}
+	s.running++
+	return s.ctx
+}
+
+// exit registers a service that ran with ctx as returned, and reports
+// whether it was stopped to restart all services.
+func (s *supervisor) exit(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	s.cond.Broadcast()
+	return context.Cause(ctx) == errRestart
+}
+
+// restart stops all services of the generation of ctx, waits for them to
+// return, calls backoff and starts a new generation. It returns
+// immediately if another service is restarting them already.
+func (s *supervisor) restart(ctx context.Context, backoff func()) {
+	s.mu.Lock()
+	if s.ctx != ctx || s.restarting {
+		s.mu.Unlock()
+		return
+	}
+	s.restarting = true
+	s.cancel(errRestart)
+	for s.running > 0 {
+		s.cond.Wait()
+	}
+	s.mu.Unlock()
+
+	backoff()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx, s.cancel = nil, nil
+	s.restarting = false
+	s.cond.Broadcast()
+}
@@ -0,0 +1,159 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_GoService(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    RestartPolicy
+		failures  int
+		wantRuns  int
+		wantErr   error
+		wantCause error
+	}{
+		{
+			name:     "recovers",
+			policy:   RestartPolicy{MaxRestarts: 3, Backoff: time.Millisecond},
+			failures: 2,
+			wantRuns: 3,
+		},
+		{
+			name:     "never_restarted",
+			policy:   RestartPolicy{},
+			failures: 2,
+			wantRuns: 1,
+			wantErr:  errInternal,
+		},
+		{
+			name:     "gives_up",
+			policy:   RestartPolicy{MaxRestarts: 2, Backoff: time.Millisecond},
+			failures: 5,
+			wantRuns: 3,
+			wantErr:  errInternal,
+		},
+		{
+			name:     "window",
+			policy:   RestartPolicy{MaxRestarts: 1, Window: time.Nanosecond, Backoff: time.Millisecond},
+			failures: 5,
+			wantRuns: 6,
+		},
+		{
+			name:     "unlimited",
+			policy:   RestartPolicy{MaxRestarts: -1, Backoff: time.Microsecond, MaxBackoff: time.Millisecond},
+			failures: 20,
+			wantRuns: 21,
+		},
+		{
+			name:     "give_up_ignore",
+			policy:   RestartPolicy{MaxRestarts: 1, GiveUp: GiveUpIgnore},
+			failures: 5,
+			wantRuns: 2,
+		},
+		{
+			name:      "give_up_cancel",
+			policy:    RestartPolicy{MaxRestarts: 1, GiveUp: GiveUpCancel},
+			failures:  5,
+			wantRuns:  2,
+			wantErr:   errInternal,
+			wantCause: errInternal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, g := New(context.Background(), Collect)
+
+			var runs int
+			g.GoService(ctx, "consumer", func(ctx context.Context) error {
+				runs++
+				if runs <= tc.failures {
+					if runs%2 == 0 {
+						panic(errInternal)
+					}
+					return errInternal
+				}
+				return nil
+			}, tc.policy)
+
+			err := g.Wait()
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Errorf("group.Wait() = %v, want %v", err, tc.wantErr)
+			}
+			if runs != tc.wantRuns {
+				t.Errorf("expected %d runs, but got %d", tc.wantRuns, runs)
+			}
+			if tc.wantCause != nil && !errors.Is(context.Cause(ctx), tc.wantCause) {
+				t.Errorf("context.Cause() = %v, want %v", context.Cause(ctx), tc.wantCause)
+			}
+		})
+	}
+}
+
+func TestRestartDelay(t *testing.T) {
+	policy := RestartPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	for n, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		if got := restartDelay(policy, n+1); got != want {
+			t.Errorf("restartDelay(%d) = %v, want %v", n+1, got, want)
+		}
+	}
+}
+
+func TestGroup_WithRestartStrategy_OneForAll(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithRestartStrategy(OneForAll))
+
+	policy := RestartPolicy{MaxRestarts: 1, Backoff: time.Millisecond}
+	started := make(chan struct{})
+	var warmerRuns, consumerRuns int
+	g.GoService(ctx, "warmer", func(ctx context.Context) error {
+		warmerRuns++
+		if warmerRuns == 1 {
+			close(started)
+			// Runs until stopped for the restart.
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}, policy)
+	g.GoService(ctx, "consumer", func(ctx context.Context) error {
+		consumerRuns++
+		if consumerRuns == 1 {
+			<-started
+			return errInternal
+		}
+		return nil
+	}, policy)
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if warmerRuns != 2 || consumerRuns != 2 {
+		t.Errorf("expected both services to be restarted once, but got %d and %d runs", warmerRuns, consumerRuns)
+	}
+}
+
+func TestGroup_WithRestartStrategy_OneForAll_TaskContext(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithRestartStrategy(OneForAll))
+
+	policy := RestartPolicy{MaxRestarts: 0}
+	ids := make(chan string, 2)
+	for _, name := range []string{"a", "b"} {
+		g.GoService(ctx, name, func(ctx context.Context) error {
+			id, _ := TaskID(ctx)
+			ids <- id
+			return nil
+		}, policy)
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	a, b := <-ids, <-ids
+	if a == "" || b == "" || a == b {
+		t.Errorf("expected each service to see its own task ID, but got %q and %q", a, b)
+	}
+}
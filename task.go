@@ -0,0 +1,207 @@
+package workgroup
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// TaskOption is a function that configures a single task of a workgroup.
+type TaskOption func(*task)
+
+// TaskRetry overrides the retry policy of the workgroup for a single
+// task. The options are applied on top of the options set with
+// WithRetry, so a task can e.g. be retried more often, or never be
+// retried with TaskRetry(Attempts(1)), while keeping the delay configured
+// for the workgroup.
+func TaskRetry(opts ...RetryOption) TaskOption {
+	return func(t *task) {
+		t.retryOptions = append(t.retryOptions, opts...)
+	}
+}
+
+// Name names a task, which identifies it in the errors reported by the
+// workgroup, see ErrorsByName, as well as in logs, metrics and profiling
+// labels. Unlike GoNamed, it works with every method launching
+// goroutines, e.g. Submit or GoKeyed.
+func Name(name string) TaskOption {
+	return func(t *task) {
+		t.name = name
+	}
+}
+
+// Weight sets the cost of a task towards the capacity set with
+// WithWeightedLimit. It defaults to 1. A task heavier than the capacity
+// only runs once no other weighted task is running.
+func Weight(n int) TaskOption {
+	return func(t *task) {
+		t.weight = max(n, 0)
+	}
+}
+
+// Tag adds a tag to a task, which subjects it to the concurrency limit set
+// for the tag with WithTagLimit, if any. A task can have several tags.
+func Tag(name string) TaskOption {
+	return func(t *task) {
+		t.tags = append(t.tags, name)
+	}
+}
+
+// PriorityLevel is the priority of a task, see Priority.
+type PriorityLevel int
+
+// Priority levels of tasks. Tasks have the Normal priority by default.
+const (
+	Low PriorityLevel = iota - 1
+	Normal
+	High
+)
+
+// Priority sets the priority of a task. When slots of the concurrency
+// limits free up, the pending tasks with the highest priority are
+// admitted first, and tasks of the same priority in order of
+// submission. It allows interactive and batch work to share a workgroup.
+func Priority(p PriorityLevel) TaskOption {
+	return func(t *task) {
+		t.priority = int(p)
+	}
+}
+
+// TaskDeadline sets the deadline of a task, by which the workgroup should
+// complete it. With the EDF scheduling order, pending tasks with the
+// earliest deadline are admitted first, see WithScheduling.
+func TaskDeadline(d time.Time) TaskOption {
+	return func(t *task) {
+		t.deadline = d
+	}
+}
+
+// TaskTimeout limits the time a task may run, including its retries: the
+// context of the task, see GoCtx, is canceled once d has elapsed since
+// the task started, which also stops retrying it. The time is measured by
+// the clock of the workgroup, see WithClock.
+func TaskTimeout(d time.Duration) TaskOption {
+	return func(t *task) {
+		t.timeout = d
+	}
+}
+
+// TaskValue sets the value associated with key in the context of a task,
+// see GoCtx, like context.WithValue, e.g. to pass a tenant or request ID
+// to the task and its retries without capturing it in the closure.
+func TaskValue(key, val any) TaskOption {
+	return func(t *task) {
+		t.values = append(t.values, [2]any{key, val})
+	}
+}
+
+// task is a single unit of work executed by the workgroup.
+type task struct {
+	id       string
+	index    int
+	name     string
+	attempts atomic.Int32
+	// submitted is the time the task was submitted, if the timings of
+	// the workgroup are recorded, see WithTaskTimings.
+	submitted time.Time
+	start     time.Time
+	// goroutine is the ID of the goroutine executing the task, and
+	// reported the number of watchdogs that reported it, see
+	// WithStallTimeout.
+	goroutine uint64
+	reported  int
+	duration  time.Duration
+	fn        func(ctx context.Context) error
+	weight    int
+	priority  int
+	deadline  time.Time
+	timeout   time.Duration
+	key       *string
+	tags      []string
+	values    [][2]any
+	// submit is the context the task was submitted with, see Hook.
+	submit context.Context
+
+	retryOptions []RetryOption
+	// then, if set, is invoked with the final error of the task once
+	// its outcome has been recorded.
+	then func(err error)
+}
+
+// info returns the description of the task passed to callbacks.
+func (t *task) info() TaskInfo {
+	return TaskInfo{
+		ID:       t.id,
+		Index:    t.index,
+		Name:     t.name,
+		Attempts: int(t.attempts.Load()),
+	}
+}
+
+// waiter returns a waiter for n of the capacity of a limiter, scheduled
+// according to the options of the task.
+func (t *task) waiter(n int) *waiter {
+	return &waiter{n: n, priority: t.priority, deadline: t.deadline}
+}
+
+// newTask creates a task of the workgroup running fn, configured with
+// opts.
+func (g *Group) newTask(fn func() error, opts []TaskOption) *task {
+	return g.newTaskContext(func(context.Context) error {
+		return fn()
+	}, opts)
+}
+
+// newTaskContext is like newTask, for a function receiving the context of
+// the task.
+func (g *Group) newTaskContext(fn func(ctx context.Context) error, opts []TaskOption) *task {
+	// The index is assigned once the task is launched, see run.
+	t := &task{id: newTaskID(), fn: fn, index: -1, weight: 1}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if g.timed {
+		t.submitted = g.clock().Now()
+	}
+	return t
+}
+
+// taskKey is the context key of the task whose context it is. The task
+// is stored rather than its ID, which would be allocated as an interface.
+type taskKey struct{}
+
+// newTaskID returns a new random task ID.
+func newTaskID() string {
+	var id [16]byte
+	hex.Encode(id[:], binary.BigEndian.AppendUint64(make([]byte, 0, 8), rand.Uint64()))
+	return string(id[:])
+}
+
+// TaskID returns the ID of the task of a workgroup whose context is ctx,
+// see TaskInfo.ID, and reports whether there is one. It allows to
+// correlate e.g. the logs of a downstream call with the task of the
+// fan-out that made it.
+func TaskID(ctx context.Context) (string, bool) {
+	t, ok := ctx.Value(taskKey{}).(*task)
+	if !ok {
+		return "", false
+	}
+	return t.id, true
+}
+
+// context returns the context passed to the function of t, derived from
+// parent, and the function releasing it. Its timeout, if any, is measured
+// by clock.
+func (t *task) context(parent context.Context, clock Clock) (context.Context, context.CancelFunc) {
+	ctx := context.WithValue(parent, taskKey{}, t)
+	for _, kv := range t.values {
+		ctx = context.WithValue(ctx, kv[0], kv[1])
+	}
+	if t.timeout > 0 {
+		return withTimeout(ctx, clock, t.timeout)
+	}
+	return ctx, func() {}
+}
@@ -0,0 +1,201 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskRetry(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithRetry(Attempts(3), Delay(time.Millisecond)))
+
+	var idempotent, expensive, inherited int32
+	g.Go(ctx, func() error {
+		atomic.AddInt32(&idempotent, 1)
+		return errInternal
+	}, TaskRetry(Attempts(5)))
+	g.Go(ctx, func() error {
+		atomic.AddInt32(&expensive, 1)
+		return errInternal
+	}, TaskRetry(Attempts(1)))
+	g.Go(ctx, func() error {
+		atomic.AddInt32(&inherited, 1)
+		return errInternal
+	})
+	g.Wait()
+
+	if idempotent != 5 {
+		t.Errorf("expected 5 attempts with a task override, but got %d", idempotent)
+	}
+	if expensive != 1 {
+		t.Errorf("expected 1 attempt with a task override, but got %d", expensive)
+	}
+	if inherited != 3 {
+		t.Errorf("expected 3 attempts from the group policy, but got %d", inherited)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	var (
+		mu    sync.Mutex
+		order []PriorityLevel
+	)
+	for _, p := range []PriorityLevel{Low, Normal, High, High} {
+		g.Submit(ctx, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, p)
+			return nil
+		}, Priority(p))
+	}
+	waitFor(t, func() bool {
+		g.limiter.mu.Lock()
+		defer g.limiter.mu.Unlock()
+		return len(g.limiter.waiters) == 4
+	})
+	close(release)
+	g.Wait()
+
+	if want := []PriorityLevel{High, High, Normal, Low}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected the goroutines to run in order %v, but got %v", want, order)
+	}
+}
+
+func TestGroup_Name(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithQueue(1, QueueBlock))
+
+	g.Go(ctx, func() error {
+		return errInternal
+	}, Name("go"))
+	g.Submit(ctx, func() error {
+		return errInvalid
+	}, Name("submit"))
+	g.GoKeyed(ctx, "key", func() error {
+		return nil
+	}, Name("keyed"))
+	g.Wait()
+
+	errs := g.ErrorsByName()
+	if !errors.Is(errs["go"], errInternal) {
+		t.Errorf("expected the error of the named goroutine, but got %v", errs["go"])
+	}
+	if !errors.Is(errs["submit"], errInvalid) {
+		t.Errorf("expected the error of the named submitted function, but got %v", errs["submit"])
+	}
+}
+
+func TestTaskID(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	var id string
+	g.GoService(ctx, "service", func(ctx context.Context) error {
+		id, _ = TaskID(ctx)
+		return Permanent(errInternal)
+	}, RestartPolicy{})
+	g.Go(ctx, func() error {
+		return errInvalid
+	})
+	err := g.Wait()
+
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		t.Fatalf("expected an *AggregateError, but got %v", err)
+	}
+	ids := make(map[string]bool)
+	for _, te := range aggErr.TaskErrors() {
+		if len(te.ID) != 16 {
+			t.Errorf("expected a 16 characters task ID, but got %q", te.ID)
+		}
+		ids[te.ID] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected unique task IDs, but got %v", ids)
+	}
+	if !ids[id] {
+		t.Errorf("expected the context of the task to carry its ID %q, but got %v", id, ids)
+	}
+	if _, ok := TaskID(ctx); ok {
+		t.Errorf("expected the context of the workgroup not to carry a task ID")
+	}
+}
+
+func TestTaskTimeout(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	g.GoCtx(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Name("slow"), TaskTimeout(10*time.Millisecond))
+	g.GoCtx(ctx, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected no deadline for the task without timeout")
+		}
+		return nil
+	})
+
+	err := g.Wait()
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name != "slow" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("group.Wait() = %v, want the slow task to time out", err)
+	}
+}
+
+func TestTaskTimeout_Clock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx, g := New(context.Background(), Collect, WithClock(clock))
+
+	g.GoCtx(ctx, func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok || !deadline.Equal(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)) {
+			t.Errorf("ctx.Deadline() = %v, %v, want the deadline of the fake clock", deadline, ok)
+		}
+		// The timer of the fake clock fires at once.
+		<-ctx.Done()
+		return ctx.Err()
+	}, TaskTimeout(time.Hour))
+
+	if err := g.Wait(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("group.Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTaskValue(t *testing.T) {
+	type tenantKey struct{}
+	ctx, g := New(context.Background(), Collect, WithRetry(Attempts(2), Delay(time.Millisecond)))
+
+	var tenants []any
+	for _, tenant := range []string{"acme", "globex"} {
+		g.GoCtx(ctx, func(ctx context.Context) error {
+			if ctx.Value(tenantKey{}) == "acme" {
+				// Retried with the same value.
+				return errInternal
+			}
+			return nil
+		}, TaskValue(tenantKey{}, tenant), Name(tenant))
+	}
+	g.GoCtx(ctx, func(ctx context.Context) error {
+		tenants = append(tenants, ctx.Value(tenantKey{}))
+		return nil
+	})
+
+	err := g.Wait()
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name != "acme" || taskErr.Attempts != 2 {
+		t.Errorf("group.Wait() = %v, want the acme task to fail after 2 attempts", err)
+	}
+	if !reflect.DeepEqual(tenants, []any{nil}) {
+		t.Errorf("expected no value for the task without TaskValue, but got %v", tenants)
+	}
+}
@@ -0,0 +1,73 @@
+package workgroup
+
+import (
+	"slices"
+	"time"
+)
+
+// TaskTiming describes when a goroutine of a workgroup ran, see
+// WithTaskTimings.
+type TaskTiming struct {
+	TaskInfo
+	// Submitted is the time the goroutine was submitted to the
+	// workgroup.
+	Submitted time.Time
+	// Start is the time the goroutine started executing, once admitted
+	// by the concurrency limits.
+	Start time.Time
+	// End is the time the goroutine finished, including all retries.
+	End time.Time
+	// Err is the error of the goroutine as collected by the workgroup,
+	// or nil if it succeeded.
+	Err error
+}
+
+// Queued returns the time the goroutine waited to be admitted.
+func (t TaskTiming) Queued() time.Duration {
+	return t.Start.Sub(t.Submitted)
+}
+
+// Duration returns the time the goroutine took to execute, including
+// all retries.
+func (t TaskTiming) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// WithTaskTimings makes the workgroup record the timing of every
+// goroutine, available from Report, e.g. to find out which goroutines
+// dominate the wall clock time of a batch job. Timings are kept for every
+// goroutine until Reset, so memory grows with the number of goroutines.
+func WithTaskTimings() Option {
+	return func(g *Group) {
+		g.timed = true
+	}
+}
+
+// Report returns the timings of the goroutines of the workgroup that have
+// finished, in order of submission, if WithTaskTimings is set. It is safe
+// to call at any time.
+func (g *Group) Report() []TaskTiming {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	timings := slices.Clone(g.timings)
+	slices.SortFunc(timings, func(a, b TaskTiming) int {
+		return a.Index - b.Index
+	})
+	return timings
+}
+
+// recordTiming records the timing of t, which has finished with err.
+func (g *Group) recordTiming(t *task, err error) {
+	if !g.timed {
+		return
+	}
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	g.timings = append(g.timings, TaskTiming{
+		TaskInfo:  t.info(),
+		Submitted: t.submitted,
+		Start:     t.start,
+		End:       t.start.Add(t.duration),
+		Err:       err,
+	})
+}
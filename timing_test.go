@@ -0,0 +1,43 @@
+package workgroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_WithTaskTimings(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx, g := New(context.Background(), Collect,
+		WithClock(&fakeClock{now: start}),
+		WithLimit(1),
+		WithTaskTimings(),
+		WithRetry(Attempts(2), Delay(time.Hour), MaxJitter(0)),
+	)
+
+	var attempts int
+	g.GoNamed(ctx, "slow", func() error {
+		attempts++
+		if attempts == 1 {
+			return errInternal
+		}
+		return nil
+	})
+	g.GoNamed(ctx, "queued", func() error {
+		return errInvalid
+	}, TaskRetry(Attempts(1)))
+	g.Wait()
+
+	report := g.Report()
+	if len(report) != 2 {
+		t.Fatalf("expected the timings of 2 goroutines, but got %d", len(report))
+	}
+	slow, queued := report[0], report[1]
+	if slow.Name != "slow" || slow.Attempts != 2 || slow.Duration() != time.Hour || slow.Err != nil {
+		t.Errorf("unexpected timing of the slow goroutine: %+v", slow)
+	}
+	if queued.Name != "queued" || queued.Queued() != time.Hour || !errors.Is(queued.Err, errInvalid) {
+		t.Errorf("unexpected timing of the queued goroutine: %+v", queued)
+	}
+}
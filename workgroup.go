@@ -5,7 +5,7 @@
 // errgroup.Group library available in `x/sync`, but with modified
 // behavior in how it handles goroutine errors and cancellation.
 //
-// This package offers two different failure modes:
+// This package offers five different failure modes:
 //
 //   - Collect - All goroutines are allowed to complete, and all errors
 //     encountered across different goroutines are collected. Wait()
@@ -16,6 +16,25 @@
 //     context of all remaining goroutines and causes Wait() to return
 //     that error.
 //
+//   - CollectAndCancel - The first error encountered immediately
+//     cancels the context of all remaining goroutines, but all
+//     goroutines are still waited for and Wait() returns a joined error
+//     of all errors, including those caused by the cancellation.
+//
+//   - FirstSuccess - The first goroutine to succeed immediately cancels
+//     the context of all remaining goroutines and causes Wait() to
+//     return nil. If no goroutine succeeds, Wait() returns ErrNoSuccess
+//     joined with the errors of all failures.
+//
+//   - Quorum - Once K goroutines succeed (see WithQuorum), the context
+//     of all remaining goroutines is canceled and Wait() returns nil.
+//     Otherwise, Wait() returns ErrQuorumNotReached joined with the
+//     errors of all failures.
+//
+// RunningTasks and DumpTasks describe the running goroutines of a
+// workgroup, e.g. to find out why it hangs, including their stack traces
+// with WithTaskStacks.
+//
 // `workgroup.Group` also provides options to set a retry policy for
 // individual goroutines within the group. A zero-value `Group` will
 // collect all errors and return them as a single error.
@@ -24,9 +43,12 @@ package workgroup
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
 	"sync"
-
-	"github.com/avast/retry-go"
+	"sync/atomic"
+	"time"
 )
 
 // FailureMode defines how the workgroup handles errors encountered
@@ -40,27 +62,309 @@ const (
 	// FailFast instructs the workgroup to halt execution and cancel
 	// all remaining goroutines upon the first error encountered.
 	FailFast
+	// CollectAndCancel instructs the workgroup to cancel all remaining
+	// goroutines upon the first error encountered, while still waiting
+	// for all of them and collecting all errors, i.e. "stop starting new
+	// work, but report everything that went wrong".
+	CollectAndCancel
+	// FirstSuccess instructs the workgroup to cancel all remaining
+	// goroutines as soon as any goroutine succeeds. Errors are only
+	// reported if no goroutine succeeds, in which case the error of the
+	// workgroup matches ErrNoSuccess.
+	FirstSuccess
+	// Quorum instructs the workgroup to cancel all remaining goroutines
+	// as soon as the number of goroutines configured by WithQuorum have
+	// succeeded. Errors are only reported if the quorum is not reached,
+	// in which case the error of the workgroup matches
+	// ErrQuorumNotReached.
+	Quorum
 )
 
+// ErrDraining is returned, or recorded as the error of the goroutine,
+// when submitting a goroutine to a workgroup that is draining, see Drain.
+var ErrDraining = errors.New("workgroup: group is draining")
+
+// ErrGroupClosed is returned, or recorded as the error of the goroutine,
+// when submitting a goroutine to a closed workgroup, see Close.
+var ErrGroupClosed = errors.New("workgroup: group is closed")
+
+// ErrWaitTimeout is returned by WaitContext when its context is done
+// before the goroutines of the workgroup have completed.
+var ErrWaitTimeout = errors.New("workgroup: wait timed out")
+
+// ErrNoSuccess is returned by Wait in FirstSuccess mode, joined with the
+// errors of the goroutines, if no goroutine succeeded, including when all
+// errors were ignored or no goroutine was launched.
+var ErrNoSuccess = errors.New("workgroup: no goroutine succeeded")
+
+// ErrQuorumNotReached is returned by Wait in Quorum mode, joined with the
+// errors of the goroutines, if fewer goroutines than configured by
+// WithQuorum have succeeded, including when no goroutine failed.
+var ErrQuorumNotReached = errors.New("workgroup: quorum not reached")
+
+// ErrGoexit is recorded as the error of a goroutine that called
+// runtime.Goexit, e.g. through t.FailNow or t.Fatal in a test helper,
+// instead of returning.
+var ErrGoexit = errors.New("workgroup: goroutine called runtime.Goexit")
+
 // Option is a function that configures a workgroup.
 type Option func(*Group)
 
 // WithLimit sets the maximum number of goroutines that can execute
-// concurrently within the workgroup.
+// concurrently within the workgroup. The limit can be changed later with
+// SetLimit.
 func WithLimit(n int) Option {
 	return func(g *Group) {
-		g.sem = make(chan struct{}, n)
+		g.limiter = newLimiter(n)
 	}
 }
 
 // WithRetry sets the retry policy for individual goroutines
 // within the workgroup.
-func WithRetry(opts ...retry.Option) Option {
+//
+// Without any options, or without Attempts, goroutines are not retried.
+// If the error of a failed attempt implements
+//
+//	interface{ RetryAfter() time.Duration }
+//
+// the next attempt is delayed by the returned duration instead of the
+// configured backoff, so that rate-limited upstreams are respected.
+// Options from the retry-go library can be used through the separate
+// retrygo module.
+//
+// With a Retrier, e.g. one from the retrygo module, the retry engine of
+// the workgroup is bypassed: WithRetryBudget, WithRetryRateLimit and
+// WithOnRetry have no effect, and retries are not counted in the Retried
+// stat.
+func WithRetry(opts ...RetryOption) Option {
 	return func(g *Group) {
 		g.retryOptions = append(g.retryOptions, opts...)
 	}
 }
 
+// WithExponentialBackoff retries failing goroutines up to attempts times
+// in total, waiting base after the first attempt and doubling the delay
+// after every further attempt, up to max. Jitter is added as with
+// MaxJitter. It is a shorthand for WithRetry, and goroutines can override
+// it with TaskRetry.
+func WithExponentialBackoff(base, max time.Duration, attempts int) Option {
+	return WithRetry(Attempts(attempts), Delay(base), MaxDelay(max), DelayType(BackOffDelay))
+}
+
+// WithConstantBackoff retries failing goroutines up to attempts times in
+// total, waiting d between attempts. It is a shorthand for WithRetry, and
+// goroutines can override it with TaskRetry.
+func WithConstantBackoff(d time.Duration, attempts int) Option {
+	return WithRetry(Attempts(attempts), Delay(d), MaxJitter(0), DelayType(FixedDelay))
+}
+
+// WithRetryBudget limits the total number of retries across all
+// goroutines of the workgroup to n. Once the budget is exhausted, failing
+// goroutines are no longer retried and fail immediately, which prevents
+// retry storms when a dependency shared by all goroutines is down.
+func WithRetryBudget(n int) Option {
+	return func(g *Group) {
+		g.retryBudget = newRetryBudget(n)
+	}
+}
+
+// WithRetryRateLimit limits the rate of retries across all goroutines of
+// the workgroup to n per the given period, e.g. 10 per second. Retries
+// are spaced out evenly and wait, after their backoff delay, for their
+// turn. It protects downstream systems from synchronized bursts of
+// retries, independently of the concurrency limit.
+func WithRetryRateLimit(n int, per time.Duration) Option {
+	return func(g *Group) {
+		g.retryLimiter = newRetryLimiter(n, per)
+	}
+}
+
+// WithDeadline sets a deadline on the context of the workgroup. Once it
+// is exceeded, the context is done with context.DeadlineExceeded and the
+// error returned from Wait includes a *DeadlineError describing the
+// goroutines that were still running, which composing context.WithDeadline
+// externally loses.
+func WithDeadline(deadline time.Time) Option {
+	return func(g *Group) {
+		g.deadline = deadline
+	}
+}
+
+// WithTimeout is like WithDeadline, with a deadline d after the creation
+// of the workgroup, or after Reset.
+func WithTimeout(d time.Duration) Option {
+	return func(g *Group) {
+		g.timeout = d
+	}
+}
+
+// WithErrorThreshold sets the number of errors the workgroup tolerates
+// before canceling all remaining goroutines. The (n+1)-th error cancels
+// the workgroup context, while all errors are still collected and
+// returned from Wait().
+//
+// It has no effect in FailFast mode, which always cancels on the first
+// error.
+func WithErrorThreshold(n int) Option {
+	return func(g *Group) {
+		g.cancelAfter = n + 1
+	}
+}
+
+// WithQuorum sets the number of goroutines that need to succeed for a
+// workgroup in Quorum mode to succeed. It defaults to 1, which makes
+// Quorum mode behave like FirstSuccess.
+//
+// The workgroup is canceled early, and Wait() returns an error, once the
+// quorum can no longer be reached. Since the total number of goroutines is
+// only known once Wait() has been called, this is not detected before.
+func WithQuorum(k int) Option {
+	return func(g *Group) {
+		g.quorumSize = k
+	}
+}
+
+// WithCancelOnSuccess cancels all remaining goroutines as soon as any
+// goroutine succeeds. Unlike FirstSuccess mode, the workgroup still waits
+// for every goroutine and Wait() returns all errors encountered,
+// including those of goroutines that failed before or because of the
+// cancellation.
+func WithCancelOnSuccess() Option {
+	return func(g *Group) {
+		g.cancelOnSuccess = true
+	}
+}
+
+// WithMaxErrors sets the maximum number of errors collected by the
+// workgroup. Errors beyond the first n are counted but not retained, and
+// the error returned from Wait() ends with a summary of how many errors
+// were omitted. This keeps memory and the size of the error bounded for
+// very large workgroups.
+func WithMaxErrors(n int) Option {
+	return func(g *Group) {
+		g.maxErrors = n
+	}
+}
+
+// WithErrorSampling makes the workgroup retain a uniformly random sample
+// of at most k errors instead of all of them, while still counting every
+// error. It keeps memory bounded for workgroups with tens of thousands of
+// tasks, while giving more representative diagnostics than WithMaxErrors,
+// which retains the first errors. Retained errors are not kept in the
+// order they occurred.
+func WithErrorSampling(k int) Option {
+	return func(g *Group) {
+		g.sampleSize = k
+	}
+}
+
+// WithErrorAggregator sets the function used to combine the errors
+// collected by the workgroup into the error returned from Wait(), instead
+// of returning an *AggregateError.
+//
+// The function is only called if at least one error was collected. It
+// is not used in FailFast mode, where Wait() returns the first error.
+func WithErrorAggregator(fn func(errs []error) error) Option {
+	return func(g *Group) {
+		g.aggregate = fn
+	}
+}
+
+// WithIgnoreErrors sets a predicate for errors the workgroup ignores.
+// Errors matching the predicate are dropped: they are not collected,
+// do not count towards any threshold and do not trigger cancellation in
+// FailFast mode. A typical use is ignoring the context.Canceled errors of
+// goroutines canceled because of a sibling:
+//
+//	workgroup.WithIgnoreErrors(func(err error) bool {
+//		return errors.Is(err, context.Canceled)
+//	})
+func WithIgnoreErrors(predicate func(err error) bool) Option {
+	return func(g *Group) {
+		g.ignoreErr = predicate
+	}
+}
+
+// WithErrorMapper sets a function that transforms the error of every
+// failed task before it is collected, e.g. to annotate it with the task
+// metadata or a tenant ID. If the function returns nil, the error is
+// ignored as if it matched WithIgnoreErrors.
+//
+// The mapper is called after the WithIgnoreErrors predicate, and the
+// error it returns is wrapped in a *TaskError like any other task error.
+func WithErrorMapper(fn func(info TaskInfo, err error) error) Option {
+	return func(g *Group) {
+		g.mapErr = fn
+	}
+}
+
+// WithOnError registers a callback invoked synchronously, in the goroutine
+// of the task, whenever a task fails and before its error is collected.
+// It is useful to log failures or emit metrics as they happen instead of
+// after Wait() returns.
+//
+// The callback receives the error after WithIgnoreErrors and
+// WithErrorMapper have been applied, and is not invoked for ignored
+// errors. It may be called concurrently from multiple goroutines.
+func WithOnError(fn func(info TaskInfo, err error)) Option {
+	return func(g *Group) {
+		g.onError = fn
+	}
+}
+
+// WithOnRetry registers a callback invoked synchronously, in the goroutine
+// of the task, whenever a failed attempt is about to be retried. It
+// receives the error of the failed attempt and the delay before the next
+// one; info.Attempts is the number of attempts made so far. It is useful
+// to emit metrics or to find out why a workgroup is slow to complete.
+// It may be called concurrently from multiple goroutines.
+func WithOnRetry(fn func(info TaskInfo, err error, delay time.Duration)) Option {
+	return func(g *Group) {
+		g.onRetry = fn
+	}
+}
+
+// WithOnFinish registers a callback invoked exactly once when the
+// workgroup finishes, with the error returned from Wait and the final
+// Stats, after the cleanup functions registered with Defer and before
+// Wait returns. It allows to emit a single summary log or metric without
+// wrapping Wait at every call site.
+func WithOnFinish(fn func(err error, stats Stats)) Option {
+	return func(g *Group) {
+		g.onFinish = fn
+	}
+}
+
+// WithErrorClassifier sets a function that assigns every task error to a
+// class, e.g. "timeout" or "not-found". The workgroup counts the errors
+// per class, which gives a complete summary even for errors that are not
+// retained in FailFast mode or because of WithMaxErrors. The counts are
+// available from ErrorClasses and AggregateError.Classes.
+//
+// The classifier receives the error after WithIgnoreErrors and
+// WithErrorMapper have been applied.
+func WithErrorClassifier(classify func(err error) string) Option {
+	return func(g *Group) {
+		g.classify = classify
+	}
+}
+
+// Grouper is the interface of the core methods of a Group. Code launching
+// goroutines through a Grouper rather than a *Group can be unit tested
+// with a test double, e.g. the synchronous Group of the workgrouptest
+// package.
+type Grouper interface {
+	// Go launches fn in a new goroutine, see Group.Go.
+	Go(ctx context.Context, fn func() error, opts ...TaskOption)
+	// Wait blocks until all goroutines have completed and returns their
+	// error, see Group.Wait.
+	Wait() error
+	// Cancel cancels the context of the goroutines, see Group.Cancel.
+	Cancel()
+}
+
+var _ Grouper = (*Group)(nil)
+
 // A Group is a collection of goroutines working on subtasks that are part of
 // the same overall task.
 //
@@ -69,103 +373,1212 @@ func WithRetry(opts ...retry.Option) Option {
 //   - Does not cancel on error (uses `Collect` failure mode).
 //   - Does not retry on error.
 type Group struct {
-	cancel func()
+	name   string
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
 
-	err     error
-	errOnce sync.Once
-	errLock sync.Mutex
+	errs      []error
+	dropped   int
+	classes   map[string]int
+	errStream *errStream
+	errLock   sync.Mutex
+	start     time.Time
+	end       time.Time
+	waiting   bool
+	finished  bool
+	draining  bool
+	closed    bool
+	doneCh    chan struct{}
+	// waited, if set, is closed once Wait has returned, see SubGroup.
+	waited   chan struct{}
+	stopping chan struct{}
+	deferred []func()
+	// finishOnce guards finish, so that concurrent calls to Wait all
+	// return after the cleanup functions.
+	finishOnce sync.Once
+	active     map[*task]struct{}
+	// inflight is the number of goroutines Wait waits for, and idle
+	// signals Barrier when it drops to zero.
+	inflight int
+	idle     *sync.Cond
+	timings  []TaskTiming
+	// panicked is the first panic of a goroutine, unless the panic policy
+	// is PanicRecover.
+	panicked *PanicError
+	// cause is the error passed to CancelCause.
+	cause error
+	// overdue describes the goroutines that were still running when the
+	// context of the workgroup exceeded its deadline.
+	overdue   []TaskInfo
+	submitted int
+	successes int
+	failures  int
+	ignored   int
 
-	wg  sync.WaitGroup
-	sem chan struct{}
+	wg          sync.WaitGroup
+	pending     atomic.Int64
+	running     atomic.Int64
+	blocked     atomic.Int64
+	retries     atomic.Int64
+	detached    atomic.Int64
+	limiter     *limiter
+	weighted    *limiter
+	queue       *limiter
+	queuePolicy QueuePolicy
+	order       SchedulingOrder
+	pool        *pool
+	memoryLimit uint64
+	tagLimits   map[string]*limiter
+	rampUp      *rampUp
+	startLimit  *startLimit
+	sharedLimit bool
+	// keys maps the key of every running keyed task to the tasks
+	// queued after it, see GoKeyed.
+	keys map[string][]*task
 
 	failureMode  FailureMode
-	retryOptions []retry.Option
+	retryOptions []RetryOption
+	retryBudget  *retryBudget
+	retryLimiter *retryLimiter
+	// cancelAfter is the number of errors after which the workgroup is
+	// canceled. Zero disables the threshold.
+	cancelAfter int
+	failureRate *failureRate
+	quorumSize  int
+	breaker     *circuitBreaker
+	supervisor  *supervisor
+	actors      *actors
+	signals     *shutdownSignals
+	watchdogs   []*watchdog
+	// stacks is whether the stack traces of the running goroutines can be
+	// captured, see WithTaskStacks.
+	stacks bool
+	// watching is closed once the workgroup has finished, to stop the
+	// watchdogs.
+	watching chan struct{}
+
+	cancelOnSuccess bool
+	maxErrors       int
+	aggregate       func(errs []error) error
+	ignoreErr       func(err error) bool
+	mapErr          func(info TaskInfo, err error) error
+	onError         func(info TaskInfo, err error)
+	onRetry         func(info TaskInfo, err error, delay time.Duration)
+	onFinish        func(err error, stats Stats)
+	hooks           []Hook
+	metrics         Metrics
+	logger          *logMetrics
+	profilingLabels bool
+	timed           bool
+	listeners       []Listener
+	panicPolicy     PanicPolicy
+	panicHandler    func(v any, stack []byte, info TaskInfo)
+	classify        func(err error) string
+	sampleSize      int
+
+	deadline time.Time
+	timeout  time.Duration
+	// expiry is the deadline of the current context of the workgroup.
+	expiry time.Time
+
+	clk Clock
 }
 
 // New creates a new workgroup with the specified failure mode and options.
 // It returns a context that is derived from `ctx`.
 // The derived context is canceled when the workgroup finishes
 // or is canceled explicitly.
+// When the workgroup cancels the context because of a failing goroutine,
+// e.g. in FailFast mode, context.Cause returns that goroutine's error.
 // If no Retry is specified, the default behavior is no retries.
+// Retries between attempts are interrupted once the derived context is
+// done.
 func New(ctx context.Context, mode FailureMode, opts ...Option) (context.Context, *Group) {
-	ctx, cancel := context.WithCancel(ctx)
-
 	g := &Group{
-		cancel:      cancel,
+		parent:      ctx,
 		failureMode: mode,
-		retryOptions: []retry.Option{
-			retry.Attempts(1),
-			retry.LastErrorOnly(true),
-			retry.Context(ctx),
-		},
 	}
 	for _, opt := range opts {
 		opt(g)
 	}
+	ctx, g.cancel = g.derive(ctx)
+	g.ctx = ctx
+	g.setup()
 	return ctx, g
 }
 
+// setup completes the configuration of a workgroup created with options.
+func (g *Group) setup() {
+	if g.memoryLimit > 0 {
+		g.admission().memoryLimit = g.memoryLimit
+	}
+	if g.rampUp != nil {
+		g.rampUp.clock = g.clock()
+		g.admission().ramp = g.rampUp
+	}
+	if g.startLimit != nil {
+		g.startLimit.clock = g.clock()
+		g.admission().startLimit = g.startLimit
+	}
+	for _, l := range []*limiter{g.limiter, g.weighted} {
+		if l != nil {
+			l.order = g.order
+		}
+	}
+	for _, l := range g.tagLimits {
+		l.order = g.order
+	}
+	g.start = g.clock().Now()
+	// Shutdown signals cancel the context of the workgroup, without which
+	// they would only be trapped, see NewCollector.
+	if g.signals != nil && g.ctx != nil {
+		g.notify()
+	}
+	if len(g.watchdogs) > 0 {
+		g.watching = make(chan struct{})
+		go g.watchStalls(g.watching)
+	}
+}
+
 // Go launches a new goroutine within the workgroup to execute the
 // provided function. The function may be retried according to the
 // workgroup's retry policy.
 // It blocks until the new goroutine can be added without exceeding the
-// configured concurrency limit.
-func (g *Group) Go(ctx context.Context, fn func() error) {
-	g.add()
-	go func() {
-		defer g.done()
-
-		err := retry.Do(fn, g.retryOptions...)
-		if err != nil {
-			g.errLock.Lock()
-			defer g.errLock.Unlock()
-
-			if g.failureMode == FailFast {
-				// In FailFast mode, cancel the workgroup context and
-				// store the first error encountered.
-				g.errOnce.Do(func() {
-					g.err = err
-					// Signal cancellation to all goroutines.
-					g.Cancel()
-				})
-				return
-			}
+// configured concurrency limit, see GoErr and TryGo to not block
+// indefinitely. Callers blocked in Go are admitted in the order in which
+// they called it, among tasks of the same priority, so that no producer
+// is starved under load.
+// Task options configure the new goroutine individually, and compose,
+// e.g. g.Go(ctx, fn, Name("sync-user"), Priority(High), Weight(3)):
+// see Name, Priority, Weight, Tag, TaskDeadline, TaskTimeout and
+// TaskRetry, which overrides the retry policy of the workgroup.
+func (g *Group) Go(ctx context.Context, fn func() error, opts ...TaskOption) {
+	g.launch(ctx, g.newTask(fn, opts))
+}
+
+// GoCtx is like Go, but passes the context of the task to fn. It is
+// derived from the context of the workgroup, and carries the values of
+// the task, e.g. its ID, see TaskID. It saves capturing a context in the
+// closure, which may not be the one of the workgroup.
+func (g *Group) GoCtx(ctx context.Context, fn func(ctx context.Context) error, opts ...TaskOption) {
+	g.launch(ctx, g.newTaskContext(fn, opts))
+}
+
+// GoNamed is like Go, but gives the goroutine a name that identifies it
+// in the errors reported by the workgroup, see ErrorsByName. It is a
+// shorthand for the Name task option.
+func (g *Group) GoNamed(ctx context.Context, name string, fn func() error, opts ...TaskOption) {
+	t := g.newTask(fn, opts)
+	t.name = name
+	g.launch(ctx, t)
+}
+
+// GoErr is like Go, but stops waiting for the concurrency limit once ctx
+// or the context of the workgroup is done. In that case fn is not
+// launched and GoErr returns the cause of the cancellation, see
+// context.Cause; it returns nil once fn is launched.
+func (g *Group) GoErr(ctx context.Context, fn func() error, opts ...TaskOption) error {
+	return g.launchErr(ctx, g.newTask(fn, opts))
+}
+
+// launchErr is like launch, but returns an error instead of blocking
+// once ctx or the context of the workgroup is done, see GoErr.
+func (g *Group) launchErr(ctx context.Context, t *task) error {
+	if err := g.accepting(); err != nil {
+		return err
+	}
+	t.submit = ctx
+	g.emitSubmit(t)
+	g.blocked.Add(1)
+	release, err := g.addContext(ctx, t)
+	g.blocked.Add(-1)
+	if err != nil {
+		return err
+	}
+	g.run(t, release)
+	return nil
+}
+
+// TryGo is like Go, but does not block: it only launches fn if the new
+// goroutine can be added without exceeding the configured concurrency
+// limits, and reports whether it did. It allows to shed load at the
+// submission point instead of queuing callers.
+func (g *Group) TryGo(ctx context.Context, fn func() error, opts ...TaskOption) bool {
+	if g.accepting() != nil {
+		return false
+	}
+	t := g.newTask(fn, opts)
+	t.submit = ctx
+	release, ok := g.tryAdd(t)
+	if !ok {
+		return false
+	}
+	g.emitSubmit(t)
+	g.run(t, release)
+	return true
+}
+
+// launch starts t in a new goroutine of the workgroup, once admitted by
+// the concurrency limits.
+func (g *Group) launch(ctx context.Context, t *task) {
+	t.submit = ctx
+	if err := g.accepting(); err != nil {
+		g.reject(t, err)
+		return
+	}
+	g.emitSubmit(t)
+	g.spawn(t)
+}
+
+// spawn starts the accepted task t in a new goroutine of the workgroup,
+// once admitted by the concurrency limits.
+func (g *Group) spawn(t *task) {
+	g.blocked.Add(1)
+	release := g.add(t)
+	g.blocked.Add(-1)
+	g.run(t, release)
+}
+
+// accepting returns an error if the workgroup no longer accepts new
+// goroutines.
+func (g *Group) accepting() error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.acceptingLocked()
+}
+
+// acceptingLocked is like accepting. g.errLock must be held.
+func (g *Group) acceptingLocked() error {
+	if g.closed {
+		return ErrGroupClosed
+	}
+	if g.draining {
+		return ErrDraining
+	}
+	return nil
+}
+
+// reject handles t submitted with a method that does not return an error,
+// like Go, while the workgroup does not accept goroutines for the reason
+// err. They are recorded as failed with err, ErrGroupClosed or
+// ErrDraining, so that they are not silently missed.
+func (g *Group) reject(t *task, err error) {
+	g.errLock.Lock()
+	t.index = g.submitted
+	g.submitted++
+	g.errLock.Unlock()
+	g.record(t, err)
+}
+
+// run starts the admitted task t in a new goroutine, or on a worker of
+// the pool of the workgroup.
+func (g *Group) run(t *task, release func()) {
+	g.errLock.Lock()
+	if g.start.IsZero() {
+		g.start = g.clock().Now()
+	}
+	t.index = g.submitted
+	g.submitted++
+	g.errLock.Unlock()
+
+	g.pending.Add(1)
+	if g.pool != nil {
+		g.pool.submit(func() { g.execute(t, release) })
+		return
+	}
+	go g.execute(t, release)
+}
+
+// execute runs the admitted task t including all retries, records its
+// outcome and calls release.
+func (g *Group) execute(t *task, release func()) {
+	if t.key != nil {
+		// Runs after done, so that the next task can take the slot.
+		defer g.next(t)
+	}
+	defer g.done(release)
 
-			// In Collect mode, aggregate errors from all goroutines.
-			g.err = errors.Join(g.err, err)
+	g.pending.Add(-1)
+	g.running.Add(1)
+	defer g.running.Add(-1)
+	t.start = g.clock().Now()
+	if g.stacks {
+		// Costly, so only done if the stack trace can be captured.
+		t.goroutine = goroutineID()
+	}
+	g.track(t)
+	defer g.untrack(t)
+	metrics := g.instruments()
+	metrics.TaskStarted(t.info())
+	g.emit(func(l Listener) { l.OnStart(t.info()) })
+
+	ctx, cancel := t.context(g.context(), g.clock())
+	defer cancel()
+	ctx = g.startHooks(ctx, t)
+
+	// Task options are applied last, overriding the workgroup's.
+	policy := newRetryPolicy(g.retryOptions, t.retryOptions)
+	policy.budget = g.retryBudget
+	policy.limiter = g.retryLimiter
+	policy.clock = g.clock()
+	policy.onRetry = func(err error, delay time.Duration) {
+		g.retries.Add(1)
+		g.retryHooks(ctx, t, err, delay)
+		metrics.RetryAttempted(t.info(), err)
+		g.emit(func(l Listener) { l.OnRetry(t.info(), err, delay) })
+		if g.onRetry != nil {
+			g.onRetry(t.info(), err, delay)
+		}
+	}
+	fn := g.retryable(ctx, t)
+	if g.breaker != nil {
+		fn = g.breaker.wrap(g.clock(), fn)
+	}
+	// runtime.Goexit cannot be recovered, but still runs the deferred
+	// calls: the task is then completed with ErrGoexit.
+	exited := true
+	defer func() {
+		if exited {
+			g.complete(ctx, t, metrics, ErrGoexit)
 		}
 	}()
+	var err error
+	if g.profilingLabels {
+		g.labeled(t, func() {
+			err = policy.do(ctx, fn)
+		})
+	} else {
+		err = policy.do(ctx, fn)
+	}
+	exited = false
+	g.complete(ctx, t, metrics, err)
+}
+
+// complete records the outcome err of t, whose context is ctx, once its
+// function has returned.
+func (g *Group) complete(ctx context.Context, t *task, metrics Metrics, err error) {
+	t.duration = g.clock().Now().Sub(t.start)
+
+	err = g.record(t, err)
+	g.recordTiming(t, err)
+	g.finishHooks(ctx, t, err)
+	metrics.TaskFinished(t.info(), t.duration, err)
+	g.emitFinish(t, err)
+	if t.then != nil {
+		t.then(err)
+	}
+}
+
+// track registers t as running.
+func (g *Group) track(t *task) {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	if g.active == nil {
+		g.active = make(map[*task]struct{})
+	}
+	g.active[t] = struct{}{}
+}
+
+// untrack unregisters t once it has finished, and reports it as overdue
+// if it was started before the deadline of the workgroup and finished
+// after it.
+func (g *Group) untrack(t *task) {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	delete(g.active, t)
+	if !g.expiry.IsZero() && t.start.Before(g.expiry) && !g.clock().Now().Before(g.expiry) {
+		g.overdue = append(g.overdue, t.info())
+	}
+}
+
+// activeTasks describes the running tasks, in order of submission.
+// g.errLock must be held.
+func (g *Group) activeTasks() []TaskInfo {
+	infos := make([]TaskInfo, 0, len(g.active))
+	for t := range g.active {
+		infos = append(infos, t.info())
+	}
+	slices.SortFunc(infos, func(a, b TaskInfo) int {
+		return a.Index - b.Index
+	})
+	return infos
+}
+
+// filter applies WithIgnoreErrors and WithErrorMapper to the error of t,
+// returning nil if the error is ignored.
+func (g *Group) filter(t *task, err error) error {
+	if err != nil && g.ignoreErr != nil && g.ignoreErr(err) {
+		return nil
+	}
+	if err != nil && g.mapErr != nil {
+		return g.mapErr(t.info(), err)
+	}
+	return err
+}
+
+// record stores the outcome of a single task according to the failure
+// mode of the workgroup. It returns the error of the task as seen by the
+// workgroup, which is either nil or a *TaskError.
+func (g *Group) record(t *task, err error) error {
+	var panicErr *PanicError
+	if err != nil && g.panicPolicy != PanicRecover {
+		panicErr = asPanicError(err)
+	}
+	filtered := g.filter(t, err)
+	ignored := err != nil && filtered == nil
+	err = filtered
+	if err != nil && g.onError != nil {
+		g.onError(t.info(), err)
+	}
+	var class string
+	if err != nil && g.classify != nil {
+		class = g.classify(err)
+	}
+
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	if panicErr != nil {
+		if g.panicked == nil {
+			g.panicked = panicErr
+		}
+		if g.panicPolicy == PanicCancel {
+			g.cancelCause(panicErr)
+		}
+	}
+	if ignored {
+		// Ignored errors count neither as success nor as failure.
+		g.ignored++
+		g.checkQuorum()
+		return nil
+	}
+	if err != nil {
+		err = &TaskError{
+			TaskInfo: t.info(),
+			Duration: t.duration,
+			Err:      err,
+		}
+		if g.errStream != nil {
+			g.errStream.push(err)
+		}
+		if g.classify != nil {
+			if g.classes == nil {
+				g.classes = make(map[string]int)
+			}
+			g.classes[class]++
+		}
+	}
+
+	if g.failureRate != nil && g.failureRate.observe(err != nil) {
+		// The failure rate indicates a systemic failure, stop the
+		// remaining goroutines.
+		g.cancelCause(err)
+	}
+
+	if err == nil {
+		g.successes++
+		if g.cancelOnSuccess && g.successes == 1 {
+			g.Cancel()
+		}
+		if q := g.quorum(); q > 0 && g.successes == q {
+			// In FirstSuccess and Quorum mode, the remaining goroutines
+			// are no longer needed once enough of them succeeded.
+			g.Cancel()
+		}
+		return nil
+	}
+
+	g.failures++
+	if g.failureMode == FailFast {
+		// In FailFast mode, cancel the workgroup context and
+		// store the first error encountered.
+		if len(g.errs) == 0 {
+			g.errs = append(g.errs, err)
+			// Signal cancellation to all goroutines, with the error
+			// as the cause.
+			g.cancelCause(err)
+		} else {
+			// Keep track of how many errors followed the first one.
+			g.dropped++
+		}
+		return err
+	}
+
+	// In all other modes, aggregate errors from all goroutines.
+	switch {
+	case g.sampleSize > 0 && len(g.errs) >= g.sampleSize:
+		// Reservoir sampling: the n-th error replaces a random retained
+		// error with probability sampleSize/n, so that every error is
+		// equally likely to be retained.
+		if i := rand.IntN(len(g.errs) + g.dropped + 1); i < len(g.errs) {
+			g.errs[i] = err
+		}
+		g.dropped++
+	case g.maxErrors > 0 && len(g.errs) >= g.maxErrors:
+		g.dropped++
+	default:
+		g.errs = append(g.errs, err)
+	}
+	if g.failureMode == CollectAndCancel && g.failures == 1 {
+		g.cancelCause(err)
+	}
+	if g.cancelAfter > 0 && g.failures == g.cancelAfter {
+		// Too many errors, stop the remaining goroutines.
+		g.cancelCause(err)
+	}
+	g.checkQuorum()
+	return err
+}
+
+// quorum returns the number of goroutines that need to succeed for the
+// workgroup to succeed early, or zero if the failure mode has no quorum.
+func (g *Group) quorum() int {
+	switch g.failureMode {
+	case FirstSuccess:
+		return 1
+	case Quorum:
+		return max(g.quorumSize, 1)
+	}
+	return 0
+}
+
+// checkQuorum cancels the workgroup once its quorum can no longer be
+// reached. It must be called with errLock held.
+func (g *Group) checkQuorum() {
+	q := g.quorum()
+	if q == 0 || !g.waiting || g.successes >= q {
+		return
+	}
+	if g.submitted-g.failures-g.ignored < q {
+		g.Cancel()
+	}
 }
 
 // Wait blocks until all goroutines in the workgroup have completed.
 // It returns nil if all goroutines were successful, or an error
 // aggregating the errors encountered, depending on the configured
 // failure mode.
+//
+// In FailFast mode, the error is the *TaskError of the first failing
+// goroutine. In the other modes, it is an *AggregateError holding all
+// collected errors, unless WithErrorAggregator is used.
+//
+// Wait may be called by multiple goroutines concurrently, and again
+// after it has returned: all calls return the same error.
+//
+// With the PanicRepanic policy, Wait panics if a goroutine panicked, see
+// WithPanicPolicy.
 func (g *Group) Wait() error {
+	err := g.wait()
+	g.repanic()
+	return err
+}
+
+// wait is like Wait, but never panics.
+func (g *Group) wait() error {
+	g.errLock.Lock()
+	// All goroutines have been submitted, which allows detecting an
+	// unreachable quorum.
+	g.waiting = true
+	g.checkQuorum()
+	g.errLock.Unlock()
+
 	g.wg.Wait()
+	if g.pool != nil {
+		g.pool.stop()
+	}
 	// Ensure context is canceled after all goroutines finish.
 	g.Cancel()
-	return g.err
+	g.finishOnce.Do(g.finish)
+
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.result()
+}
+
+// finish runs the cleanup functions registered with Defer, marks the
+// workgroup as finished and calls the WithOnFinish callback, once all
+// goroutines have completed.
+func (g *Group) finish() {
+	for {
+		g.errLock.Lock()
+		deferred := g.deferred
+		g.deferred = nil
+		if len(deferred) == 0 {
+			// Keep errLock, so that no function is registered before
+			// the workgroup is marked as finished.
+			break
+		}
+		g.errLock.Unlock()
+		for i := len(deferred) - 1; i >= 0; i-- {
+			deferred[i]()
+		}
+	}
+
+	g.end = g.clock().Now()
+	g.finished = true
+	if g.waited != nil {
+		close(g.waited)
+	}
+	if g.watching != nil {
+		close(g.watching)
+	}
+	if g.errStream != nil {
+		g.errStream.close()
+	}
+	err, stats := g.result(), g.stats()
+	g.errLock.Unlock()
+
+	if g.onFinish != nil {
+		g.onFinish(err, stats)
+	}
+}
+
+// Defer registers fn to be called when the workgroup finishes, once all
+// goroutines have completed and before Wait returns, whatever the error.
+// Functions are called in the reverse order of their registration, like
+// deferred calls, which gives tasks sharing resources, e.g. a connection
+// pool, a reliable teardown point tied to the lifetime of the workgroup.
+// If the workgroup has already finished, fn is called immediately.
+func (g *Group) Defer(fn func()) {
+	g.errLock.Lock()
+	if !g.finished {
+		g.deferred = append(g.deferred, fn)
+		g.errLock.Unlock()
+		return
+	}
+	g.errLock.Unlock()
+	fn()
+}
+
+// WaitContext is like Wait, but returns early if ctx is done before all
+// goroutines have completed. In that case, the error matches
+// ErrWaitTimeout with errors.Is and is joined with the current error of
+// the workgroup, see Err. The goroutines keep running, and Wait or
+// WaitContext can be called again later.
+func (g *Group) WaitContext(ctx context.Context) error {
+	select {
+	case <-g.Done():
+		return g.Wait()
+	case <-ctx.Done():
+		return errors.Join(ErrWaitTimeout, g.Err())
+	}
+}
+
+// Done returns a channel that is closed once all goroutines in the
+// workgroup have completed and the workgroup has finished, i.e. when Wait
+// returns, so that coordinators can select on the completion of the
+// workgroup. The first call to Done waits for the workgroup in the
+// background as if Wait was called, so all goroutines must have been
+// submitted by then. The error is available from Err.
+func (g *Group) Done() <-chan struct{} {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	if g.doneCh == nil {
+		done := make(chan struct{})
+		g.doneCh = done
+		go func() {
+			g.wait()
+			close(done)
+		}()
+	}
+	return g.doneCh
+}
+
+// Close permanently seals the workgroup: goroutines submitted afterwards
+// are not launched. GoErr and Submit return ErrGroupClosed and TryGo
+// returns false, while goroutines submitted with Go or GoNamed are
+// recorded as failed with ErrGroupClosed, so that a late call racing
+// with Wait is reported rather than silently missed. Close does not wait
+// for the running goroutines, and Reset does not reopen the workgroup.
+func (g *Group) Close() {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	g.closed = true
+}
+
+// Shutdown gracefully shuts the workgroup down: it stops accepting new
+// goroutines like Drain, closes the channel returned by Stopping to ask
+// the running goroutines to finish, and waits for them until ctx is done.
+// The goroutines still running then are canceled through the context of
+// the workgroup, with the cause of ctx, and waited for.
+//
+// Shutdown returns the same error as Wait if all goroutines finished in
+// time, or a *ShutdownError describing the canceled goroutines. Like
+// Wait, it panics with the PanicRepanic policy if a goroutine panicked.
+func (g *Group) Shutdown(ctx context.Context) error {
+	err := g.shutdown(ctx)
+	g.repanic()
+	return err
+}
+
+// shutdown is like Shutdown, but never panics.
+func (g *Group) shutdown(ctx context.Context) error {
+	g.errLock.Lock()
+	g.draining = true
+	g.stop()
+	g.errLock.Unlock()
+
+	select {
+	case <-g.Done():
+		return g.wait()
+	case <-ctx.Done():
+	}
+
+	g.errLock.Lock()
+	killed := g.activeTasks()
+	g.errLock.Unlock()
+	g.cancelCause(context.Cause(ctx))
+
+	err := g.wait()
+	if len(killed) == 0 {
+		return err
+	}
+	return &ShutdownError{Killed: killed, Err: err}
+}
+
+// Stopping returns a channel that is closed when Shutdown is called, on
+// which long-running goroutines can select to finish gracefully before
+// being canceled.
+func (g *Group) Stopping() <-chan struct{} {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	if g.stopping == nil {
+		g.stopping = make(chan struct{})
+	}
+	return g.stopping
+}
+
+// stop closes the stopping channel. g.errLock must be held.
+func (g *Group) stop() {
+	if g.stopping == nil {
+		g.stopping = make(chan struct{})
+	}
+	select {
+	case <-g.stopping:
+	default:
+		close(g.stopping)
+	}
+}
+
+// Drain stops accepting new goroutines and waits for the queued and
+// running ones to finish, then returns the same error as Wait. After
+// Drain is called, Go and GoNamed do not launch their function but
+// record it as failed with ErrDraining, while GoErr and Submit return
+// ErrDraining and TryGo returns false. Callers already blocked in Go are
+// still admitted.
+//
+// If ctx is done first, Drain returns the cause of ctx joined with the
+// current error of the workgroup, see Err, while the goroutines keep
+// running. It allows a graceful rolling restart.
+func (g *Group) Drain(ctx context.Context) error {
+	g.errLock.Lock()
+	g.draining = true
+	g.errLock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return g.Wait()
+	case <-ctx.Done():
+		return errors.Join(context.Cause(ctx), g.Err())
+	}
+}
+
+// Reset prepares the workgroup for another round of goroutines after
+// Wait has returned, keeping its configuration. It clears the collected
+// errors and the counters, refills the retry budget, and returns a new
+// context derived from the context passed to New, which replaces the one
+// canceled by Wait. Reusing a workgroup avoids allocating a new one in a
+// hot loop.
+//
+// Reset must not be called while goroutines of the workgroup are running.
+func (g *Group) Reset() context.Context {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	parent := g.parent
+	if parent == nil {
+		parent = context.Background()
+	}
+	g.ctx, g.cancel = g.derive(parent)
+
+	g.errs = nil
+	g.dropped = 0
+	g.classes = nil
+	g.errStream = nil
+	g.start = g.clock().Now()
+	g.end = time.Time{}
+	g.waiting = false
+	g.finished = false
+	g.draining = false
+	g.doneCh = nil
+	g.waited = nil
+	g.deferred = nil
+	g.finishOnce = sync.Once{}
+	g.stopping = nil
+	g.actors = nil
+	g.submitted = 0
+	g.successes = 0
+	g.failures = 0
+	g.ignored = 0
+	g.cause = nil
+	g.timings = nil
+	g.panicked = nil
+	g.overdue = nil
+	g.retries.Store(0)
+	if g.failureRate != nil {
+		g.failureRate.reset()
+	}
+	if g.retryBudget != nil {
+		g.retryBudget.reset()
+	}
+	if g.signals != nil {
+		g.notify()
+	}
+	if len(g.watchdogs) > 0 {
+		g.watching = make(chan struct{})
+		go g.watchStalls(g.watching)
+	}
+	return g.ctx
+}
+
+// Err returns the current error of the workgroup without waiting for its
+// goroutines to complete, i.e. the first error in FailFast mode or the
+// errors collected so far in the other modes. It returns nil if no
+// goroutine has failed yet, or if the quorum has already been reached.
+//
+// Err allows long-running coordinators to poll the health of the
+// workgroup while it is still running.
+func (g *Group) Err() error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.result()
+}
+
+// result returns the error of the workgroup according to its failure
+// mode. It must be called with errLock held.
+func (g *Group) result() error {
+	q := g.quorum()
+	if q > 0 && g.successes >= q {
+		return nil
+	}
+	// The reasons for which the workgroup was stopped come first.
+	var errs []error
+	if g.finished {
+		switch g.failureMode {
+		case FirstSuccess:
+			errs = append(errs, ErrNoSuccess)
+		case Quorum:
+			errs = append(errs, ErrQuorumNotReached)
+		}
+	}
+	if g.cause != nil {
+		errs = append(errs, g.cause)
+	}
+	if len(g.overdue) > 0 {
+		overdue := slices.Clone(g.overdue)
+		slices.SortFunc(overdue, func(a, b TaskInfo) int {
+			return a.Index - b.Index
+		})
+		errs = append(errs, &DeadlineError{Running: overdue})
+	}
+	err := g.joinErrors()
+	switch {
+	case len(errs) == 0:
+		return err
+	case len(errs) == 1 && err == nil:
+		return errs[0]
+	}
+	return errors.Join(append(errs, err)...)
+}
+
+// Errors returns the individual errors encountered by the goroutines of
+// the workgroup, in the order they occurred. In FailFast mode, it holds at
+// most the first error. Errors omitted because of WithMaxErrors or
+// WithErrorSampling are not included.
+//
+// Errors is typically called after Wait, but it is safe to call at any
+// time, in which case it returns the errors encountered so far.
+func (g *Group) Errors() []error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// ErrorsByName returns the errors of the goroutines launched with
+// GoNamed, keyed by their name. Goroutines that succeeded, or whose errors
+// were not retained (see Errors), are not included. If several failed
+// goroutines share a name, their errors are joined.
+//
+// Like Errors, it is safe to call at any time.
+func (g *Group) ErrorsByName() map[string]error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	errs := make(map[string]error)
+	for _, err := range g.errs {
+		name := err.(*TaskError).Name
+		if name == "" {
+			continue
+		}
+		// Several tasks may share a name, keep all of their errors.
+		if prev, ok := errs[name]; ok {
+			err = errors.Join(prev, err)
+		}
+		errs[name] = err
+	}
+	return errs
+}
+
+// Pending returns the number of goroutines accepted by the workgroup that
+// have not started executing yet, e.g. queued with Submit or GoKeyed, or
+// waiting for a worker of the pool.
+//
+// Pending, Running and BlockedSubmitters allow producers to adapt their
+// rate to the backpressure of the workgroup. They are safe to call at any
+// time.
+func (g *Group) Pending() int {
+	return int(g.pending.Load())
+}
+
+// Running returns the number of goroutines of the workgroup currently
+// executing their function, including retries.
+func (g *Group) Running() int {
+	return int(g.running.Load())
+}
+
+// BlockedSubmitters returns the number of callers currently blocked in Go,
+// GoNamed or GoErr, waiting for the concurrency limits.
+func (g *Group) BlockedSubmitters() int {
+	return int(g.blocked.Load())
+}
+
+// Suppressed returns the number of task errors that occurred but were not
+// retained by the workgroup: in FailFast mode, the errors that followed
+// the first one, and in the other modes, the errors beyond the limit set
+// with WithMaxErrors or WithErrorSampling, see also Stats.
+//
+// Like Errors, it is safe to call at any time.
+func (g *Group) Suppressed() int {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.dropped
+}
+
+// ErrorClasses returns the number of task errors per class, as assigned
+// by the classifier set with WithErrorClassifier. It returns nil if no
+// classifier is set.
+//
+// Like Errors, it is safe to call at any time.
+func (g *Group) ErrorClasses() map[string]int {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.errorClasses()
+}
+
+// errorClasses returns a copy of the error counts per class. It must be
+// called with errLock held.
+func (g *Group) errorClasses() map[string]int {
+	if g.classify == nil {
+		return nil
+	}
+	classes := make(map[string]int, len(g.classes))
+	for class, n := range g.classes {
+		classes[class] = n
+	}
+	return classes
+}
+
+// joinErrors combines the errors collected by the workgroup into the
+// error returned from Wait(). It must be called with errLock held.
+func (g *Group) joinErrors() error {
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.failureMode == FailFast {
+		return g.errs[0]
+	}
+
+	if g.aggregate != nil {
+		errs := append([]error(nil), g.errs...)
+		if g.dropped > 0 {
+			errs = append(errs, fmt.Errorf("and %d more errors", g.dropped))
+		}
+		return g.aggregate(errs)
+	}
+	return &AggregateError{
+		Errors:    append([]error(nil), g.errs...),
+		Omitted:   g.dropped,
+		Succeeded: g.successes,
+		Failed:    g.failures,
+		Classes:   g.errorClasses(),
+		Duration:  g.elapsed(),
+	}
+}
+
+// derive returns the context of the workgroup derived from parent, with
+// the deadline set by WithDeadline or WithTimeout, if any, which it
+// stores in g.expiry. g.errLock must be held, or g must not be shared
+// yet.
+func (g *Group) derive(parent context.Context) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	deadline := g.deadline
+	if g.timeout > 0 {
+		if d := g.clock().Now().Add(g.timeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	g.expiry = deadline
+	if deadline.IsZero() {
+		return ctx, cancel
+	}
+	ctx, cancelDeadline := withDeadline(ctx, g.clock(), deadline)
+	return ctx, func(cause error) {
+		cancel(cause)
+		cancelDeadline()
+	}
+}
+
+// context returns the context derived by New, or the background context
+// for a zero-value Group.
+func (g *Group) context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
 }
 
 // Cancel cancels the workgroup context, signaling all running
 // goroutines to stop.
 func (g *Group) Cancel() {
+	g.cancelCause(nil)
+}
+
+// CancelCause is like Cancel, but sets err as the cause of the
+// cancellation, which is retrievable with context.Cause on the context of
+// the workgroup and is included in the error returned from Wait, so that
+// the reason for stopping the workgroup is not lost. It has no effect if
+// the workgroup was already canceled.
+func (g *Group) CancelCause(err error) {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	if g.context().Err() != nil {
+		return
+	}
+	g.cause = err
+	g.cancelCause(err)
+}
+
+// cancelCause cancels the workgroup context with the given cause, which
+// is retrievable with context.Cause. A nil cause sets the cause to
+// context.Canceled.
+func (g *Group) cancelCause(cause error) {
 	if g.cancel != nil {
-		g.cancel()
+		g.cancel(cause)
+	}
+}
+
+// add admits t into the workgroup, waiting for the concurrency limits of
+// the workgroup, if any. It returns the function releasing the capacity
+// taken by t, to call in done, as the limits may be set in the meantime.
+func (g *Group) add(t *task) (release func()) {
+	release, _ = g.acquire(context.Background(), t)
+	return release
+}
+
+// addContext is like add, but stops waiting once ctx or the context of
+// the workgroup is done.
+func (g *Group) addContext(ctx context.Context, t *task) (release func(), err error) {
+	gctx := g.context()
+	for _, c := range []context.Context{ctx, gctx} {
+		if c.Err() != nil {
+			return nil, context.Cause(c)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(gctx, func() {
+		cancel(context.Cause(gctx))
+	})
+	defer stop()
+	return g.acquire(ctx, t)
+}
+
+// acquire takes the capacity needed by t from the concurrency limits of
+// the workgroup, unless ctx is done first.
+func (g *Group) acquire(ctx context.Context, t *task) (release func(), err error) {
+	holds := g.holds(t)
+	for i, h := range holds {
+		if err := h.l.acquire(ctx, t.waiter(h.n)); err != nil {
+			releaseAll(holds[:i])
+			return nil, err
+		}
+	}
+	return g.admit(holds), nil
+}
+
+// tryAdd is like add, but does not wait: it reports whether t was
+// admitted.
+func (g *Group) tryAdd(t *task) (release func(), ok bool) {
+	holds := g.holds(t)
+	for i, h := range holds {
+		if !h.l.tryAcquire(h.n) {
+			releaseAll(holds[:i])
+			return nil, false
+		}
 	}
+	return g.admit(holds), true
+}
+
+// hold is the capacity n of a limiter taken by a task.
+type hold struct {
+	l *limiter
+	n int
 }
 
-func (g *Group) add() {
-	if g.sem != nil {
-		g.sem <- struct{}{}
+// holds returns the capacity of the concurrency limits needed by t, in
+// the order in which it is taken. The limits of the tags come first, so
+// that tasks waiting for a tag do not hold slots of the workgroup.
+func (g *Group) holds(t *task) []hold {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	var holds []hold
+	for _, tag := range t.tags {
+		if l := g.tagLimits[tag]; l != nil {
+			holds = append(holds, hold{l: l, n: 1})
+		}
+	}
+	if g.limiter != nil {
+		holds = append(holds, hold{l: g.limiter, n: 1})
+	}
+	if g.weighted != nil {
+		holds = append(holds, hold{l: g.weighted, n: t.weight})
 	}
-	g.wg.Add(1)
+	return holds
 }
 
-func (g *Group) done() {
-	if g.sem != nil {
-		<-g.sem
+func releaseAll(holds []hold) {
+	for i := len(holds) - 1; i >= 0; i-- {
+		holds[i].l.release(holds[i].n)
 	}
-	g.wg.Done()
+}
+
+// admit adds a task, which has taken holds, to the workgroup.
+func (g *Group) admit(holds []hold) (release func()) {
+	g.enter()
+	return func() {
+		releaseAll(holds)
+	}
+}
+
+func (g *Group) done(release func()) {
+	release()
+	g.leave()
 }
@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
-
-	"github.com/avast/retry-go"
 )
 
 var (
@@ -64,6 +65,93 @@ func TestWorkGroup_Collect_MultipleErrors(t *testing.T) {
 	}
 }
 
+func TestGroup_Errors(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		return nil
+	})
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 2: %w", errInvalid)
+	})
+	g.Wait()
+
+	errs := g.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("group.Errors() returned %d errors, want 2", len(errs))
+	}
+	if !errors.Is(errs[0], errInternal) {
+		t.Errorf("errors.Is(errs[0], errInternal) = false, want true")
+	}
+	if !errors.Is(errs[1], errInvalid) {
+		t.Errorf("errors.Is(errs[1], errInvalid) = false, want true")
+	}
+}
+
+func TestGroup_ErrorsByName(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	g.GoNamed(ctx, "users", func() error {
+		return fmt.Errorf("sync users: %w", errInternal)
+	})
+	g.GoNamed(ctx, "orders", func() error {
+		return nil
+	})
+	g.GoNamed(ctx, "invoices", func() error {
+		return fmt.Errorf("sync invoices: %w", errInvalid)
+	})
+	g.Go(ctx, func() error {
+		return errors.New("anonymous")
+	})
+	g.Wait()
+
+	errs := g.ErrorsByName()
+	if len(errs) != 2 {
+		t.Fatalf("group.ErrorsByName() = %v, want 2 entries", errs)
+	}
+	if !errors.Is(errs["users"], errInternal) {
+		t.Errorf("errors.Is(errs[users], errInternal) = false, want true")
+	}
+	if !errors.Is(errs["invoices"], errInvalid) {
+		t.Errorf("errors.Is(errs[invoices], errInvalid) = false, want true")
+	}
+	if err, ok := errs["orders"]; ok {
+		t.Errorf("errs[orders] = %v, want no entry", err)
+	}
+}
+
+func TestGroup_Err(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	if err := g.Err(); err != nil {
+		t.Fatalf("group.Err() = %v, want nil", err)
+	}
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		return errInternal
+	})
+	g.Go(ctx, func() error {
+		<-release
+		return errInvalid
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for g.Err() == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	err := g.Err()
+	if !errors.Is(err, errInternal) || errors.Is(err, errInvalid) {
+		t.Errorf("group.Err() = %v, want only %v", err, errInternal)
+	}
+
+	close(release)
+	g.Wait()
+	if err := g.Err(); !errors.Is(err, errInvalid) {
+		t.Errorf("group.Err() = %v, want %v after Wait", err, errInvalid)
+	}
+}
+
 func TestWorkGroup_FailFast(t *testing.T) {
 	var (
 		count       int32
@@ -94,6 +182,51 @@ func TestWorkGroup_FailFast(t *testing.T) {
 	}
 }
 
+func TestWorkGroup_FailFast_CancelCause(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast)
+
+	var cause error
+	started := make(chan struct{})
+	g.Go(ctx, func() error {
+		close(started)
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+		return ctx.Err()
+	})
+	<-started
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+
+	err := g.Wait()
+	if !errors.Is(cause, errInternal) {
+		t.Errorf("context.Cause(ctx) = %v, want %v", cause, errInternal)
+	}
+	if cause != err {
+		t.Errorf("context.Cause(ctx) = %v, want the error returned from Wait: %v", cause, err)
+	}
+}
+
+func TestWorkGroup_FailFast_Suppressed(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast, WithLimit(1))
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			return fmt.Errorf("error %d: %w", i, errInternal)
+		})
+	}
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+	if got := g.Suppressed(); got != 4 {
+		t.Errorf("group.Suppressed() = %d, want 4", got)
+	}
+	if got := g.Stats().Suppressed; got != 4 {
+		t.Errorf("group.Stats().Suppressed = %d, want 4", got)
+	}
+}
+
 // TestWorkgroup_FailFastMode tests that the workgroup stops on the first error in FailFast mode.
 func TestWorkgroup_FailFast_SingleError(t *testing.T) {
 	ctx := context.Background()
@@ -122,6 +255,190 @@ func TestWorkgroup_FailFast_SingleError(t *testing.T) {
 	}
 }
 
+func TestWorkGroup_CollectAndCancel(t *testing.T) {
+	ctx, g := New(context.Background(), CollectAndCancel)
+
+	started := make(chan struct{})
+	g.Go(ctx, func() error {
+		close(started)
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("interrupted: %w", context.Cause(ctx))
+		}
+	})
+	<-started
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+
+	err := g.Wait()
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		t.Fatalf("group.Wait() = %v, want an *AggregateError", err)
+	}
+	if len(aggErr.Errors) != 2 {
+		t.Fatalf("expected 2 errors, but got %d", len(aggErr.Errors))
+	}
+	for _, err := range aggErr.Errors {
+		if !errors.Is(err, errInternal) {
+			t.Errorf("errors.Is(%v, errInternal) = false, want true", err)
+		}
+	}
+}
+
+func TestWorkGroup_FirstSuccess(t *testing.T) {
+	var count int32
+
+	ctx, g := New(context.Background(), FirstSuccess)
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			select {
+			case <-time.After(time.Second):
+				atomic.AddInt32(&count, 1)
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if count != 0 {
+		t.Errorf("expected remaining goroutines to be cancelled, but %d ran", count)
+	}
+}
+
+func TestWorkGroup_FirstSuccess_AllFailed(t *testing.T) {
+	ctx, g := New(context.Background(), FirstSuccess)
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 2: %w", errInvalid)
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("errors.Is(err, errInvalid) = false, want true")
+	}
+}
+
+func TestWorkGroup_FirstSuccess_NoWinner(t *testing.T) {
+	ctx, g := New(context.Background(), FirstSuccess, WithIgnoreErrors(func(err error) bool {
+		return errors.Is(err, errInvalid)
+	}))
+	g.Go(ctx, func() error {
+		return errInvalid
+	})
+	if err := g.Wait(); !errors.Is(err, ErrNoSuccess) {
+		t.Errorf("group.Wait() = %v, want %v", err, ErrNoSuccess)
+	}
+
+	_, g = New(context.Background(), FirstSuccess)
+	if err := g.Wait(); !errors.Is(err, ErrNoSuccess) {
+		t.Errorf("group.Wait() without goroutines = %v, want %v", err, ErrNoSuccess)
+	}
+}
+
+func TestWorkGroup_Quorum(t *testing.T) {
+	var count int32
+
+	ctx, g := New(context.Background(), Quorum, WithQuorum(2))
+	g.Go(ctx, func() error {
+		return fmt.Errorf("replica 1: %w", errInternal)
+	})
+	for i := 0; i < 2; i++ {
+		g.Go(ctx, func() error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+	}
+	for i := 0; i < 5; i++ {
+		g.Go(ctx, func() error {
+			select {
+			case <-time.After(time.Second):
+				atomic.AddInt32(&count, 1)
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	if count != 0 {
+		t.Errorf("expected remaining goroutines to be cancelled, but %d ran", count)
+	}
+}
+
+func TestWorkGroup_Quorum_Unreachable(t *testing.T) {
+	var canceled int32
+
+	ctx, g := New(context.Background(), Quorum, WithQuorum(2))
+	// Started before the failures, which would otherwise skip it once the
+	// workgroup is canceled.
+	started := make(chan struct{})
+	g.Go(ctx, func() error {
+		close(started)
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-ctx.Done():
+			atomic.AddInt32(&canceled, 1)
+			return ctx.Err()
+		}
+	})
+	<-started
+	g.Go(ctx, func() error {
+		return fmt.Errorf("replica 1: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		return fmt.Errorf("replica 2: %w", errInvalid)
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) || !errors.Is(err, errInvalid) {
+		t.Errorf("group.Wait() = %v, want both %v and %v", err, errInternal, errInvalid)
+	}
+	if canceled != 1 {
+		t.Error("expected the remaining goroutine to be cancelled once the quorum became unreachable")
+	}
+}
+
+func TestWorkGroup_Quorum_TooFewTasks(t *testing.T) {
+	ctx, g := New(context.Background(), Quorum, WithQuorum(3), WithIgnoreErrors(func(err error) bool {
+		return errors.Is(err, errInvalid)
+	}))
+	for i := 0; i < 2; i++ {
+		g.Go(ctx, func() error {
+			return nil
+		})
+	}
+	g.Go(ctx, func() error {
+		return errInvalid
+	})
+
+	if err := g.Wait(); !errors.Is(err, ErrQuorumNotReached) {
+		t.Errorf("group.Wait() = %v, want %v", err, ErrQuorumNotReached)
+	}
+}
+
 func TestWorkGroup_NoError(t *testing.T) {
 	ctx := context.Background()
 	ctx, g := New(ctx, Collect)
@@ -169,6 +486,296 @@ func TestGroup_WithLimit(t *testing.T) {
 	}
 }
 
+func TestGroup_WithErrorThreshold(t *testing.T) {
+	tests := []struct {
+		name         string
+		threshold    int
+		failures     int
+		wantCanceled bool
+	}{
+		{
+			name:         "below_threshold",
+			threshold:    3,
+			failures:     3,
+			wantCanceled: false,
+		},
+		{
+			name:         "above_threshold",
+			threshold:    3,
+			failures:     4,
+			wantCanceled: true,
+		},
+		{
+			name:         "zero_threshold",
+			threshold:    0,
+			failures:     1,
+			wantCanceled: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, g := New(context.Background(), Collect, WithErrorThreshold(tc.threshold))
+
+			// Started before the failures, which would otherwise skip it
+			// once the workgroup is canceled.
+			var canceled int32
+			started := make(chan struct{})
+			g.Go(ctx, func() error {
+				close(started)
+				select {
+				case <-time.After(100 * time.Millisecond):
+					return nil
+				case <-ctx.Done():
+					atomic.StoreInt32(&canceled, 1)
+					return ctx.Err()
+				}
+			})
+			<-started
+			for i := 0; i < tc.failures; i++ {
+				g.Go(ctx, func() error {
+					return fmt.Errorf("error %d: %w", i, errInternal)
+				})
+			}
+
+			err := g.Wait()
+			if !errors.Is(err, errInternal) {
+				t.Errorf("errors.Is(err, errInternal) = false, want true")
+			}
+			if got := canceled == 1; got != tc.wantCanceled {
+				t.Errorf("canceled = %v, want %v", got, tc.wantCanceled)
+			}
+		})
+	}
+}
+
+func TestGroup_WithCancelOnSuccess(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithCancelOnSuccess())
+	g.Go(ctx, func() error {
+		return fmt.Errorf("error 1: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	g.Go(ctx, func() error {
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(err, context.Canceled) = false, want true")
+	}
+}
+
+func TestGroup_WithMaxErrors(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithMaxErrors(3))
+	for i := 0; i < 10; i++ {
+		g.Go(ctx, func() error {
+			return fmt.Errorf("error %d: %w", i, errInternal)
+		})
+	}
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Fatalf("errors.Is(err, errInternal) = false, want true")
+	}
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		t.Fatalf("errors.As(err, *AggregateError) = false, want true")
+	}
+	if len(aggErr.Errors) != 3 {
+		t.Errorf("expected 3 errors, but got %d errors", len(aggErr.Errors))
+	}
+	if aggErr.Omitted != 7 {
+		t.Errorf("aggErr.Omitted = %d, want 7", aggErr.Omitted)
+	}
+	if !strings.HasSuffix(err.Error(), "\nand 7 more errors") {
+		t.Errorf("err.Error() = %q, want a summary of omitted errors", err.Error())
+	}
+}
+
+func TestGroup_WithErrorSampling(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithErrorSampling(10))
+	for i := 0; i < 1000; i++ {
+		g.Go(ctx, func() error {
+			return fmt.Errorf("error %d: %w", i, errInternal)
+		})
+	}
+
+	err := g.Wait()
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		t.Fatalf("group.Wait() = %v, want an *AggregateError", err)
+	}
+	if len(aggErr.Errors) != 10 {
+		t.Errorf("expected 10 sampled errors, but got %d", len(aggErr.Errors))
+	}
+	if aggErr.Failed != 1000 || aggErr.Omitted != 990 {
+		t.Errorf("aggErr failed/omitted = %d/%d, want 1000/990", aggErr.Failed, aggErr.Omitted)
+	}
+
+	// With 1000 errors, the sample is very unlikely to hold only the
+	// first 10 tasks.
+	var late int
+	for _, te := range aggErr.TaskErrors() {
+		if te.Index >= 10 {
+			late++
+		}
+	}
+	if late == 0 {
+		t.Error("expected the sample to include errors beyond the first 10 tasks")
+	}
+}
+
+func TestGroup_WithErrorAggregator(t *testing.T) {
+	errAggregated := errors.New("aggregated")
+
+	var got []error
+	ctx, g := New(context.Background(), Collect, WithErrorAggregator(func(errs []error) error {
+		got = errs
+		return fmt.Errorf("%d tasks failed: %w", len(errs), errAggregated)
+	}))
+	g.Go(ctx, func() error { return errInternal })
+	g.Go(ctx, func() error { return errInvalid })
+	g.Go(ctx, func() error { return nil })
+
+	err := g.Wait()
+	if !errors.Is(err, errAggregated) {
+		t.Fatalf("group.Wait() = %v, want %v", err, errAggregated)
+	}
+	if len(got) != 2 {
+		t.Errorf("aggregator received %d errors, want 2", len(got))
+	}
+}
+
+func TestGroup_WithErrorAggregator_NoError(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithErrorAggregator(func(errs []error) error {
+		t.Error("aggregator called without errors")
+		return nil
+	}))
+	g.Go(ctx, func() error { return nil })
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+}
+
+func TestGroup_WithIgnoreErrors(t *testing.T) {
+	ignore := func(err error) bool {
+		return errors.Is(err, context.Canceled) || errors.Is(err, errInvalid)
+	}
+
+	ctx, g := New(context.Background(), FailFast, WithIgnoreErrors(ignore))
+	g.Go(ctx, func() error {
+		return fmt.Errorf("not found: %w", errInvalid)
+	})
+	g.Go(ctx, func() error {
+		time.Sleep(10 * time.Millisecond)
+		return fmt.Errorf("error 2: %w", errInternal)
+	})
+	g.Go(ctx, func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if errs := g.Errors(); len(errs) != 1 {
+		t.Errorf("group.Errors() = %v, want a single error", errs)
+	}
+}
+
+func TestGroup_WithErrorMapper(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithErrorMapper(func(info TaskInfo, err error) error {
+		if errors.Is(err, errInvalid) {
+			return nil
+		}
+		return fmt.Errorf("tenant-%s: %w", info.Name, err)
+	}))
+	g.GoNamed(ctx, "a", func() error { return errInternal })
+	g.GoNamed(ctx, "b", func() error { return errInvalid })
+
+	g.Wait()
+	errs := g.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("group.Errors() = %v, want a single error", errs)
+	}
+	if !errors.Is(errs[0], errInternal) {
+		t.Errorf("errors.Is(err, errInternal) = false, want true")
+	}
+	if got, want := errs[0].Error(), `task "a": tenant-a: internal`; got != want {
+		t.Errorf("err.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestGroup_WithOnError(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+
+	ctx, g := New(context.Background(), Collect, WithOnError(func(info TaskInfo, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, info.Name)
+	}))
+	g.GoNamed(ctx, "a", func() error { return errInternal })
+	g.GoNamed(ctx, "b", func() error { return nil })
+	g.Wait()
+
+	if len(failed) != 1 || failed[0] != "a" {
+		t.Errorf("OnError called for %v, want [a]", failed)
+	}
+}
+
+func TestGroup_WithErrorClassifier(t *testing.T) {
+	classify := func(err error) string {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return "timeout"
+		case errors.Is(err, errInvalid):
+			return "invalid"
+		}
+		return "other"
+	}
+
+	ctx, g := New(context.Background(), Collect, WithMaxErrors(1), WithErrorClassifier(classify))
+	for i := 0; i < 3; i++ {
+		g.Go(ctx, func() error { return context.DeadlineExceeded })
+	}
+	g.Go(ctx, func() error { return errInvalid })
+	g.Go(ctx, func() error { return errInternal })
+	g.Go(ctx, func() error { return nil })
+
+	err := g.Wait()
+	want := map[string]int{"timeout": 3, "invalid": 1, "other": 1}
+	for name, got := range map[string]map[string]int{
+		"group.ErrorClasses()": g.ErrorClasses(),
+		"aggErr.Classes":       err.(*AggregateError).Classes,
+	} {
+		if len(got) != len(want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			continue
+		}
+		for class, n := range want {
+			if got[class] != n {
+				t.Errorf("%s = %v, want %v", name, got, want)
+			}
+		}
+	}
+}
+
 func TestGroup_Cancel(t *testing.T) {
 	ctx, g := New(context.Background(), Collect)
 	var count int32
@@ -199,15 +806,15 @@ func TestGroup_Cancel(t *testing.T) {
 func TestGroup_WithRetry(t *testing.T) {
 	tests := []struct {
 		name        string
-		retryPolicy []retry.Option
+		retryPolicy []RetryOption
 		fn          func() error
 		wantCount   int32
 		wantErr     error
 	}{
 		{
 			name: "retry_happy_path",
-			retryPolicy: []retry.Option{
-				retry.Attempts(3),
+			retryPolicy: []RetryOption{
+				Attempts(3),
 			},
 			wantCount: 3,
 			fn:        func() error { return fmt.Errorf("retry_happy_path: %w", errInternal) },
@@ -215,8 +822,8 @@ func TestGroup_WithRetry(t *testing.T) {
 		},
 		{
 			name: "retry_happy_success_no_retry",
-			retryPolicy: []retry.Option{
-				retry.Attempts(100),
+			retryPolicy: []RetryOption{
+				Attempts(100),
 			},
 			wantCount: 1,
 			fn:        func() error { return nil },
@@ -260,3 +867,460 @@ func BenchmarkGo(b *testing.B) {
 	}
 	g.Wait()
 }
+
+// BenchmarkGo_Limit guards the cost of admitting and starting a goroutine,
+// which e.g. must not capture its stack trace unless needed.
+func BenchmarkGo_Limit(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Goroutines", opts: []Option{WithLimit(8)}},
+		{name: "WorkerPool", opts: []Option{WithLimit(8), WithWorkerPool(8)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ctx, g := New(context.Background(), Collect, bc.opts...)
+			b.ResetTimer()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				g.Go(ctx, func() error { return nil })
+			}
+			g.Wait()
+		})
+	}
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	release := make(chan struct{})
+	var count int32
+	for i := 0; i < 2; i++ {
+		if !g.TryGo(ctx, func() error {
+			<-release
+			atomic.AddInt32(&count, 1)
+			return nil
+		}) {
+			t.Fatalf("TryGo() = false with a free slot, want true")
+		}
+	}
+	if g.TryGo(ctx, func() error { return nil }) {
+		t.Errorf("TryGo() = true with no free slot, want false")
+	}
+	close(release)
+	g.Wait()
+
+	if count != 2 {
+		t.Errorf("expected 2 goroutines to run, but got %d", count)
+	}
+}
+
+func TestGroup_GoErr(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+
+	release := make(chan struct{})
+	if err := g.GoErr(ctx, func() error {
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("GoErr() = %v, want nil", err)
+	}
+
+	// The caller's context expires while waiting for the slot.
+	callerCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.GoErr(callerCtx, func() error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GoErr() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The workgroup is canceled while waiting for the slot.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		g.Cancel()
+	}()
+	if err := g.GoErr(context.Background(), func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("GoErr() = %v, want %v", err, context.Canceled)
+	}
+	if err := g.GoErr(context.Background(), func() error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("GoErr() on a canceled workgroup = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+}
+
+func TestGroup_Drain(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(1))
+
+	release := make(chan struct{})
+	var count int32
+	g.Go(ctx, func() error {
+		<-release
+		atomic.AddInt32(&count, 1)
+		return errInternal
+	})
+	g.Submit(ctx, func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	drained := make(chan error)
+	go func() {
+		drained <- g.Drain(context.Background())
+	}()
+	waitFor(t, func() bool { return g.accepting() != nil })
+
+	if err := g.GoErr(ctx, func() error { return nil }); !errors.Is(err, ErrDraining) {
+		t.Errorf("GoErr() while draining = %v, want %v", err, ErrDraining)
+	}
+	if err := g.Submit(ctx, func() error { return nil }); !errors.Is(err, ErrDraining) {
+		t.Errorf("Submit() while draining = %v, want %v", err, ErrDraining)
+	}
+	g.GoNamed(ctx, "late", func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	close(release)
+	err := <-drained
+	var aggErr *AggregateError
+	if !errors.Is(err, errInternal) || !errors.As(err, &aggErr) {
+		t.Fatalf("Drain() = %v, want %v", err, errInternal)
+	}
+	var late bool
+	for _, te := range aggErr.TaskErrors() {
+		late = late || te.Name == "late" && errors.Is(te, ErrDraining)
+	}
+	if !late {
+		t.Errorf("Drain() = %v, want the late goroutine to fail with %v", err, ErrDraining)
+	}
+	if stats := g.Stats(); stats.Submitted != 3 {
+		t.Errorf("expected the late goroutine to be counted as submitted, but got %d", stats.Submitted)
+	}
+	if count != 2 {
+		t.Errorf("expected the running and queued goroutines to run, but got %d", count)
+	}
+}
+
+func TestGroup_Drain_Canceled(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	release := make(chan struct{})
+	defer close(release)
+	g.Go(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Drain(drainCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGroup_Backpressure(t *testing.T) {
+	ctx, g := New(context.Background(), Collect, WithLimit(2))
+
+	release := make(chan struct{})
+	fn := func() error {
+		<-release
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		g.Go(ctx, fn)
+	}
+	for i := 0; i < 3; i++ {
+		g.Submit(ctx, fn)
+	}
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		g.Go(ctx, fn)
+	}()
+
+	waitFor(t, func() bool {
+		return g.Running() == 2 && g.Pending() == 3 && g.BlockedSubmitters() == 1
+	})
+
+	close(release)
+	<-submitted
+	g.Wait()
+	if r, p, b := g.Running(), g.Pending(), g.BlockedSubmitters(); r != 0 || p != 0 || b != 0 {
+		t.Errorf("expected no running, pending or blocked goroutines after Wait, but got %d, %d, %d", r, p, b)
+	}
+}
+
+func TestGroup_Reset(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast)
+
+	g.Go(ctx, func() error { return errInternal })
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Fatalf("group.Wait() = %v, want %v", err, errInternal)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected the context to be canceled after Wait")
+	}
+
+	for round := 0; round < 3; round++ {
+		ctx = g.Reset()
+		if ctx.Err() != nil {
+			t.Fatalf("expected a live context after Reset, but got %v", ctx.Err())
+		}
+
+		var count int32
+		for i := 0; i < 5; i++ {
+			g.Go(ctx, func() error {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				atomic.AddInt32(&count, 1)
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatalf("round %d: group.Wait() = %v, want nil", round, err)
+		}
+		if count != 5 {
+			t.Errorf("round %d: expected 5 goroutines to run, but got %d", round, count)
+		}
+		if errs := g.Errors(); len(errs) != 0 {
+			t.Errorf("round %d: expected no errors after Reset, but got %v", round, errs)
+		}
+	}
+}
+
+func TestGroup_Done(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error {
+		<-release
+		return errInternal
+	})
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			errs <- g.Wait()
+		}()
+	}
+
+	select {
+	case <-g.Done():
+		t.Fatal("expected Done not to be closed while goroutines are running")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-g.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Done to be closed once goroutines have completed")
+	}
+	if err := g.Err(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Err() = %v, want %v", err, errInternal)
+	}
+	for i := 0; i < 3; i++ {
+		if err := <-errs; !errors.Is(err, errInternal) {
+			t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+		}
+	}
+}
+
+func TestGroup_WaitContext(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	release := make(chan struct{})
+	g.Go(ctx, func() error { return errInvalid })
+	g.Go(ctx, func() error {
+		<-release
+		return errInternal
+	})
+	waitFor(t, func() bool { return errors.Is(g.Err(), errInvalid) })
+
+	waitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := g.WaitContext(waitCtx)
+	if !errors.Is(err, ErrWaitTimeout) || !errors.Is(err, errInvalid) {
+		t.Errorf("group.WaitContext() = %v, want %v and %v", err, ErrWaitTimeout, errInvalid)
+	}
+
+	close(release)
+	err = g.WaitContext(context.Background())
+	if errors.Is(err, ErrWaitTimeout) || !errors.Is(err, errInternal) {
+		t.Errorf("group.WaitContext() = %v, want %v without %v", err, errInternal, ErrWaitTimeout)
+	}
+}
+
+func TestGroup_Shutdown(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	// A cooperative goroutine finishing on Stopping, and a stuck one.
+	g.GoNamed(ctx, "consumer", func() error {
+		<-g.Stopping()
+		return nil
+	})
+	g.GoNamed(ctx, "stuck", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	waitFor(t, func() bool { return g.Running() == 2 })
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := g.Shutdown(shutdownCtx)
+
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("group.Shutdown() = %v, want a *ShutdownError", err)
+	}
+	if len(shutdownErr.Killed) != 1 || shutdownErr.Killed[0].Name != "stuck" {
+		t.Errorf("expected the stuck goroutine to be killed, but got %v", shutdownErr.Killed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("group.Shutdown() = %v, want the error of the killed goroutine", err)
+	}
+	if err := g.GoErr(ctx, func() error { return nil }); !errors.Is(err, ErrDraining) {
+		t.Errorf("GoErr() after Shutdown = %v, want %v", err, ErrDraining)
+	}
+}
+
+func TestGroup_Shutdown_Graceful(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+	g.Go(ctx, func() error {
+		<-g.Stopping()
+		return nil
+	})
+
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Errorf("group.Shutdown() = %v, want nil", err)
+	}
+}
+
+func TestGroup_Close(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	g.Go(ctx, func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Fatalf("group.Wait() = %v, want nil", err)
+	}
+	g.Close()
+
+	var ran bool
+	g.GoNamed(ctx, "late", func() error {
+		ran = true
+		return nil
+	})
+	if err := g.GoErr(ctx, func() error { return nil }); !errors.Is(err, ErrGroupClosed) {
+		t.Errorf("GoErr() after Close = %v, want %v", err, ErrGroupClosed)
+	}
+	if g.TryGo(ctx, func() error { return nil }) {
+		t.Error("TryGo() after Close = true, want false")
+	}
+
+	err := g.Wait()
+	if ran {
+		t.Error("expected the late goroutine not to run")
+	}
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name != "late" || !errors.Is(err, ErrGroupClosed) {
+		t.Errorf("group.Wait() = %v, want the late goroutine to fail with %v", err, ErrGroupClosed)
+	}
+}
+
+func TestGroup_CancelCause(t *testing.T) {
+	ctx, g := New(context.Background(), Collect)
+
+	g.Go(ctx, func() error {
+		<-ctx.Done()
+		return nil
+	})
+	g.CancelCause(errInvalid)
+	// Only the first cancellation takes effect.
+	g.CancelCause(errInternal)
+
+	if cause := context.Cause(ctx); cause != errInvalid {
+		t.Errorf("context.Cause(ctx) = %v, want %v", cause, errInvalid)
+	}
+	err := g.Wait()
+	if !errors.Is(err, errInvalid) || errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInvalid)
+	}
+}
+
+func TestGroup_Goexit(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opts   []Option
+		goexit func(ctx context.Context, g *Group)
+	}{
+		{
+			name: "Go",
+			goexit: func(ctx context.Context, g *Group) {
+				g.Go(ctx, func() error {
+					runtime.Goexit()
+					return nil
+				})
+			},
+		},
+		{
+			name: "WorkerPool",
+			opts: []Option{WithWorkerPool(1)},
+			goexit: func(ctx context.Context, g *Group) {
+				g.Go(ctx, func() error {
+					runtime.Goexit()
+					return nil
+				})
+			},
+		},
+		{
+			name: "GoHedged",
+			goexit: func(ctx context.Context, g *Group) {
+				g.GoHedged(ctx, time.Hour, func(context.Context) error {
+					runtime.Goexit()
+					return nil
+				})
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, g := New(context.Background(), Collect, test.opts...)
+
+			test.goexit(ctx, g)
+			var completed atomic.Bool
+			g.Go(ctx, func() error {
+				completed.Store(true)
+				return nil
+			})
+
+			if err := g.Wait(); !errors.Is(err, ErrGoexit) {
+				t.Errorf("group.Wait() = %v, want %v", err, ErrGoexit)
+			}
+			if !completed.Load() {
+				t.Error("expected the other goroutines to complete")
+			}
+		})
+	}
+}
+
+func TestGroup_GoCtx(t *testing.T) {
+	ctx, g := New(context.Background(), FailFast)
+
+	g.GoCtx(ctx, func(taskCtx context.Context) error {
+		if _, ok := TaskID(taskCtx); !ok {
+			t.Error("expected the context of the task to carry its ID")
+		}
+		<-taskCtx.Done()
+		return nil
+	}, Name("waiter"))
+	g.GoCtx(ctx, func(context.Context) error {
+		return errInternal
+	})
+
+	if err := g.Wait(); !errors.Is(err, errInternal) {
+		t.Errorf("group.Wait() = %v, want %v", err, errInternal)
+	}
+}
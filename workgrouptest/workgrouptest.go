@@ -0,0 +1,113 @@
+// Package workgrouptest provides a test double of a workgroup, to unit
+// test code launching goroutines through a workgroup.Grouper
+// deterministically.
+//
+//	ctx, g := workgrouptest.New(ctx, workgroup.FailFast)
+//	err := process(ctx, g, items) // calls g.Go and g.Wait
+package workgrouptest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sadlil/workgroup"
+)
+
+// Group is a workgroup.Grouper running the functions passed to Go
+// synchronously, in the calling goroutine, in the order of the calls.
+//
+// It follows the failure mode of the workgroup it stands in for, except
+// Quorum which behaves like Collect: once the context of the Group is
+// canceled, e.g. by the first error in the FailFast mode, functions
+// passed to Go are no longer called. Task options are ignored, functions
+// are never retried and their panics are not recovered.
+//
+// A zero-value Group is valid and uses the Collect failure mode.
+type Group struct {
+	mode   workgroup.FailureMode
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	calls     int
+	errs      []error
+	succeeded int
+}
+
+var _ workgroup.Grouper = (*Group)(nil)
+
+// New creates a new Group with the specified failure mode. It returns a
+// context derived from ctx, which is canceled according to the failure
+// mode or by Cancel, and once Wait returns.
+func New(ctx context.Context, mode workgroup.FailureMode) (context.Context, *Group) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, &Group{mode: mode, ctx: ctx, cancel: cancel}
+}
+
+// Go calls fn, unless the context of the Group is done, and records its
+// error.
+func (g *Group) Go(ctx context.Context, fn func() error, opts ...workgroup.TaskOption) {
+	if g.ctx != nil && g.ctx.Err() != nil {
+		return
+	}
+	g.mu.Lock()
+	index := g.calls
+	g.calls++
+	g.mu.Unlock()
+
+	err := fn()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err == nil {
+		g.succeeded++
+		if g.mode == workgroup.FirstSuccess {
+			g.Cancel()
+		}
+		return
+	}
+	g.errs = append(g.errs, &workgroup.TaskError{
+		TaskInfo: workgroup.TaskInfo{Index: index, Attempts: 1},
+		Err:      err,
+	})
+	if g.mode == workgroup.FailFast || g.mode == workgroup.CollectAndCancel {
+		g.Cancel()
+	}
+}
+
+// Wait cancels the context of the Group and returns the error of the
+// functions called by Go, like workgroup.Group.Wait: the *TaskError of
+// the first failing function in the FailFast mode, and otherwise an
+// *AggregateError holding all errors.
+func (g *Group) Wait() error {
+	g.Cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 || g.mode == workgroup.FirstSuccess && g.succeeded > 0 {
+		return nil
+	}
+	if g.mode == workgroup.FailFast {
+		return g.errs[0]
+	}
+	return &workgroup.AggregateError{
+		Errors:    g.errs,
+		Succeeded: g.succeeded,
+		Failed:    len(g.errs),
+	}
+}
+
+// Cancel cancels the context of the Group, after which Go no longer calls
+// functions.
+func (g *Group) Cancel() {
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// Calls returns the number of functions called by Go.
+func (g *Group) Calls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls
+}
@@ -0,0 +1,82 @@
+package workgrouptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sadlil/workgroup"
+)
+
+var (
+	errInternal = errors.New("internal")
+	errInvalid  = errors.New("invalid")
+)
+
+// process is the code under test, launching a goroutine per item.
+func process(ctx context.Context, g workgroup.Grouper, items []error, done *[]int) error {
+	for i, err := range items {
+		g.Go(ctx, func() error {
+			*done = append(*done, i)
+			return err
+		})
+	}
+	return g.Wait()
+}
+
+func TestGroup_Collect(t *testing.T) {
+	ctx, g := New(context.Background(), workgroup.Collect)
+
+	var done []int
+	err := process(ctx, g, []error{errInternal, nil, errInvalid}, &done)
+	var aggErr *workgroup.AggregateError
+	if !errors.As(err, &aggErr) || len(aggErr.Errors) != 2 || aggErr.Succeeded != 1 {
+		t.Fatalf("group.Wait() = %v, want an *AggregateError of 2 errors", err)
+	}
+	if !errors.Is(err, errInternal) || !errors.Is(err, errInvalid) {
+		t.Errorf("group.Wait() = %v, want %v and %v", err, errInternal, errInvalid)
+	}
+	if len(done) != 3 || done[0] != 0 || done[2] != 2 {
+		t.Errorf("expected the functions to be called in order, but got %v", done)
+	}
+	if ctx.Err() == nil {
+		t.Error("expected Wait to cancel the context")
+	}
+}
+
+func TestGroup_FailFast(t *testing.T) {
+	ctx, g := New(context.Background(), workgroup.FailFast)
+
+	var done []int
+	err := process(ctx, g, []error{nil, errInternal, errInvalid}, &done)
+	var taskErr *workgroup.TaskError
+	if !errors.As(err, &taskErr) || taskErr.Index != 1 || taskErr.Err != errInternal {
+		t.Errorf("group.Wait() = %v, want the *TaskError of the second function", err)
+	}
+	if g.Calls() != 2 {
+		t.Errorf("expected the functions after the failure not to be called, but got %d calls", g.Calls())
+	}
+}
+
+func TestGroup_FirstSuccess(t *testing.T) {
+	ctx, g := New(context.Background(), workgroup.FirstSuccess)
+
+	var done []int
+	if err := process(ctx, g, []error{errInternal, nil, nil}, &done); err != nil {
+		t.Errorf("group.Wait() = %v, want nil", err)
+	}
+	if g.Calls() != 2 {
+		t.Errorf("expected the functions after the success not to be called, but got %d calls", g.Calls())
+	}
+}
+
+func TestGroup_ZeroValue(t *testing.T) {
+	var (
+		g    Group
+		done []int
+	)
+	err := process(context.Background(), &g, []error{errInternal, errInvalid}, &done)
+	if !errors.Is(err, errInternal) || !errors.Is(err, errInvalid) {
+		t.Errorf("group.Wait() = %v, want %v and %v", err, errInternal, errInvalid)
+	}
+}